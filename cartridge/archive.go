@@ -0,0 +1,72 @@
+package cartridge
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	zipMagic  = []byte{'P', 'K', 0x03, 0x04}
+	gzipMagic = []byte{0x1F, 0x8B}
+)
+
+// romExtensions are the entries picked from an archive when no entry name
+// is given, in the order they appear in it.
+var romExtensions = []string{".nes", ".unf", ".unif", ".fds"}
+
+// extractRom returns the rom inside data when it is a zip or gzip archive,
+// or data itself otherwise.
+func extractRom(data []byte, entryName string) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		return extractZipRom(data, entryName)
+	case bytes.HasPrefix(data, gzipMagic):
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRomFile, err)
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+	return data, nil
+}
+
+func extractZipRom(data []byte, entryName string) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRomFile, err)
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isArchiveRom(file.Name, entryName) {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+	if entryName != "" {
+		return nil, fmt.Errorf("%w: archive has no entry %q", ErrInvalidRomFile, entryName)
+	}
+	return nil, fmt.Errorf("%w: archive has no rom", ErrInvalidRomFile)
+}
+
+func isArchiveRom(name string, entryName string) bool {
+	if entryName != "" {
+		return name == entryName
+	}
+	ext := strings.ToLower(path.Ext(name))
+	for _, romExt := range romExtensions {
+		if ext == romExt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package cartridge
+
+// bankAddress returns the offset inside a memory of memorySize bytes that
+// addr resolves to when the window of size bytes it falls in is mapped to
+// the given bank. Bank numbers past the end of the memory wrap around, as
+// the unconnected upper address lines do on the boards.
+func bankAddress(memorySize int, bank int, size int, addr uint16) int {
+	banks := max(memorySize/size, 1)
+	return (bank%banks)*size + int(addr)&(size-1)
+}
+
+const (
+	prgBank8kSize = 8 * 1024
+	chrBank1kSize = 1024
+	chrBank2kSize = 2 * 1024
+)
+
+// prgBank8kCount returns how many 8KB banks fit in the program rom, so the
+// fixed windows can address the last ones.
+func prgBank8kCount(rom *Rom) int {
+	return len(rom.Program) / prgBank8kSize
+}
+
+func readPrg8k(rom *Rom, bank int, addr uint16) uint8 {
+	return rom.Program[bankAddress(len(rom.Program), bank, prgBank8kSize, addr)]
+}
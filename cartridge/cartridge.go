@@ -1,10 +1,16 @@
 package cartridge
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/LucasWillBlumenau/nes/patch"
+	"github.com/LucasWillBlumenau/nes/poweron"
 )
 
 type MirroringType uint8
@@ -13,216 +19,578 @@ const (
 	VerticalMirroring MirroringType = iota
 	HorizontalMirroring
 	FourScreenMirroring
+	SingleScreenLowerMirroring
+	SingleScreenUpperMirroring
 )
 
+// nametablePage returns which 1KB nametable page the quadrant of the PPU
+// address addr is wired to. Pages 0 and 1 are the console CIRAM, 2 and 3 only
+// exist on four-screen boards.
+func (m MirroringType) nametablePage(addr uint16) int {
+	quadrant := int(addr>>10) & 0b11
+	switch m {
+	case FourScreenMirroring:
+		return quadrant
+	case VerticalMirroring:
+		return quadrant & 0b01
+	case SingleScreenLowerMirroring:
+		return 0
+	case SingleScreenUpperMirroring:
+		return 1
+	default:
+		return quadrant >> 1
+	}
+}
+
 var ErrInvalidRomFile = errors.New("invalid rom file")
 var ErrUnimplementedMapper = errors.New("unimplemented mapper")
+var ErrInvalidSaveData = errors.New("invalid save data")
+var ErrStatelessMapper = errors.New("mapper state can't be saved")
 
 const (
-	programBanksIndex      = 4
-	charactersBanksIndex   = 5
-	firstControlByteIndex  = 6
-	secondControlByteIndex = 7
-	ramBanksQuantityIndex  = 8
-
-	headersSize = 16
-	prgBankSize = 16 * 1024
-	chrBankSize = 8 * 1024
+	saveFileExtension = ".sav"
+
+	// programRamBankSize is the unit of the iNES PRG-RAM size, where zero
+	// still means one bank for compatibility with older dumps
+	programRamBankSize = 8 * 1024
+	programRamStart    = 0x6000
+	programRamEnd      = 0x8000
+	// trainerSize is the size of the iNES trainer, which copiers loaded at
+	// trainerAddr before starting the game
+	trainerSize = 512
+	trainerAddr = 0x7000
+	ciramPages  = 2
+	// fourScreenVramSize is the extra memory four-screen boards carry for
+	// the two nametables the console CIRAM has no room for.
+	fourScreenVramSize = 2 * nametablePageSize
+	nametablePageSize  = 1024
+	nametablePageMask  = nametablePageSize - 1
+	prgBankSize        = 16 * 1024
+	chrBankSize        = 8 * 1024
 )
 
-type characterMemory interface {
-	Read(addr uint16) uint8
-	Write(addr uint16, data uint8)
+// CharacterMemory is the memory behind the PPU pattern tables, addressed
+// from the start of the chip. Writes to CHR-ROM are ignored.
+type CharacterMemory interface {
+	Read(addr int) uint8
+	Write(addr int, data uint8)
+	Size() int
 }
 
-type characterRam []byte
+// CharacterRam is writable character memory.
+type CharacterRam []byte
 
-func (r characterRam) Read(addr uint16) uint8 {
+func (r CharacterRam) Read(addr int) uint8 {
 	return (r)[addr]
 }
 
-func (r characterRam) Write(addr uint16, data uint8) {
+func (r CharacterRam) Write(addr int, data uint8) {
 	(r)[addr] = data
 }
 
-type characterRom []byte
+func (r CharacterRam) Size() int {
+	return len(r)
+}
+
+// CharacterRom is read-only character memory.
+type CharacterRom []byte
 
-func (r characterRom) Read(addr uint16) uint8 {
+func (r CharacterRom) Read(addr int) uint8 {
 	return (r)[addr]
 }
 
-func (r characterRom) Write(addr uint16, data uint8) {
+func (r CharacterRom) Write(addr int, data uint8) {
 }
 
-type cartridgeHeaders struct {
-	ProgramBanksQuantity   int
-	ProgramRomSize         int
-	CharacterBanksQuantity int
-	CharacterRomSize       int
-	Mirroring              MirroringType
-	UseBatteryBackedRam    bool
-	UseCharacterRam        bool
-	UseTrainer             bool
-	RamBanksQuantity       int
-	MapperId               int
+func (r CharacterRom) Size() int {
+	return len(r)
 }
 
-type cartridgeRom struct {
-	Character characterMemory
-	Program   []byte
-	Trainers  []byte
+// characterRomAndRam backs boards carrying both a CHR-ROM and a CHR-RAM.
+// The rom is mapped first and the ram right after it, so bank numbers can
+// address either of them and only the ram part is writable.
+type characterRomAndRam struct {
+	rom CharacterMemory
+	ram CharacterRam
+}
+
+func newCharacterRomAndRam(rom CharacterMemory, ramSize int) *characterRomAndRam {
+	return &characterRomAndRam{
+		rom: rom,
+		ram: make(CharacterRam, ramSize),
+	}
+}
+
+// RamOffset returns the address where the ram starts.
+func (m *characterRomAndRam) RamOffset() int {
+	return m.rom.Size()
+}
+
+func (m *characterRomAndRam) Read(addr int) uint8 {
+	if addr < m.RamOffset() {
+		return m.rom.Read(addr)
+	}
+	return m.ram.Read(addr - m.RamOffset())
+}
+
+func (m *characterRomAndRam) Write(addr int, data uint8) {
+	if addr >= m.RamOffset() {
+		m.ram.Write(addr-m.RamOffset(), data)
+	}
+}
+
+func (m *characterRomAndRam) Size() int {
+	return m.rom.Size() + len(m.ram)
+}
+
+// Rom holds the memory chips of a cartridge, which its mapper banks into
+// the CPU and PPU address spaces.
+type Rom struct {
+	Character  CharacterMemory
+	Program    []byte
+	ProgramRam []byte
+	Trainers   []byte
+	// Disk holds the sides of disk system images, one after the other.
+	Disk []byte
 }
 
 type Cartridge struct {
-	headers cartridgeHeaders
-	mapper  mapper
+	headers  Header
+	rom      *Rom
+	mapper   Mapper
+	vram     []byte
+	game     *GameInfo
+	savePath string
+	// lastSave holds the data last written to or read from the save file,
+	// so periodic saves only touch the disk when the game wrote something
+	lastSave []byte
 }
 
-func LoadCartridgeFromRom(filePath string) (*Cartridge, error) {
-	fp, err := os.Open(filePath)
+// LoadCartridgeFromRom loads the rom file at filePath, which may also be a
+// zip or gzip archive, keeping the save file next to it. When no patch is
+// given, a patch file with the same name as the rom is applied if present,
+// and disk system images use the bios next to them if none is given.
+func LoadCartridgeFromRom(filePath string, options ...Option) (*Cartridge, error) {
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer fp.Close()
+	basePath := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	options = append([]Option{WithSavePath(basePath + saveFileExtension)}, options...)
+	opts := newLoadOptions(options)
+	if opts.fdsBios == nil {
+		bios, err := os.ReadFile(filepath.Join(filepath.Dir(filePath), fdsBiosFileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if bios != nil {
+			options = append([]Option{WithFDSBios(bios)}, options...)
+		}
+	}
+	if len(opts.patches) == 0 {
+		patchData, err := readSameNamePatch(basePath)
+		if err != nil {
+			return nil, err
+		}
+		if patchData != nil {
+			options = append(options, WithPatch(patchData))
+		}
+	}
+	return FromBytes(data, options...)
+}
+
+func readSameNamePatch(basePath string) ([]byte, error) {
+	for _, ext := range patch.Extensions {
+		data, err := os.ReadFile(basePath + ext)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return data, err
+	}
+	return nil, nil
+}
 
-	headers, err := readHeaders(fp)
+// readINES reads a rom in the iNES or NES 2.0 formats.
+func readINES(reader io.Reader) (*Header, *Rom, error) {
+	headers, err := readHeaders(reader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	rom, err := readRom(fp, headers)
+	rom, err := readRom(reader, headers)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	buf := make([]byte, 1)
-	_, err = fp.Read(buf)
+	_, err = reader.Read(buf)
 	if !errors.Is(err, io.EOF) {
-		return nil, ErrInvalidRomFile
+		return nil, nil, ErrInvalidRomFile
 	}
+	return headers, rom, nil
+}
 
-	var createMapper createMapperFn = nil
-	if len(mappers) > headers.MapperId {
-		createMapper = mappers[headers.MapperId]
+// newCartridge builds the cartridge for a parsed rom. game is the
+// information the rom file carries itself, if any, which the game database
+// takes precedence over.
+func newCartridge(headers *Header, rom *Rom, game *GameInfo, opts loadOptions) (*Cartridge, error) {
+	if !opts.withoutGameDatabase {
+		dbGame, err := applyGameDatabase(headers, rom)
+		if err != nil {
+			return nil, err
+		}
+		if dbGame != nil {
+			game = dbGame
+		}
 	}
-	if createMapper == nil {
+	allocateRam(rom, headers, opts.ramState)
+
+	createMapper, ok := findMapper(headers)
+	if !ok {
 		return nil, fmt.Errorf("%w: mapper %d not implemented", ErrUnimplementedMapper, headers.MapperId)
 	}
 	mapper := createMapper(rom, headers)
-	return &Cartridge{
-		headers: *headers,
-		mapper:  mapper,
-	}, nil
-}
-
-func readHeaders(reader io.Reader) (*cartridgeHeaders, error) {
-	headers := make([]byte, headersSize)
-	n, err := reader.Read(headers)
-	if err != nil {
-		return nil, err
+	cart := &Cartridge{
+		headers:  *headers,
+		rom:      rom,
+		mapper:   mapper,
+		vram:     newFourScreenVram(headers, mapper),
+		game:     game,
+		savePath: opts.savePath,
 	}
-
-	if n != headersSize {
-		return nil, ErrInvalidRomFile
+	if err := cart.loadSave(); err != nil {
+		return nil, err
 	}
+	cart.loadTrainer()
+	return cart, nil
+}
 
-	programBanksQuantity := int(headers[programBanksIndex])
-	charactersBanks := int(headers[charactersBanksIndex])
-	useCharacterRom := false
-	if charactersBanks == 0 {
-		charactersBanks = 1
-		useCharacterRom = true
+// readFull fills buf from reader, treating a rom that ends early as an
+// invalid file instead of an I/O error.
+func readFull(reader io.Reader, buf []byte) error {
+	_, err := io.ReadFull(reader, buf)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrInvalidRomFile
 	}
+	return err
+}
 
-	charactersSize := charactersBanks * chrBankSize
-	programSize := programBanksQuantity * prgBankSize
-	firstControlByte := headers[firstControlByteIndex]
-	secondControlByte := headers[secondControlByteIndex]
-	ramBanksQuantity := int(headers[ramBanksQuantityIndex])
+// remainingReader is implemented by readers that know how many bytes they
+// still hold, like bytes.Reader.
+type remainingReader interface {
+	Len() int
+}
 
-	useVerticalMirroring := (firstControlByte & 0b1) == 1
-	useFourScreenMirroring := (firstControlByte & 0b1000) == 1
-	mirroring := HorizontalMirroring
-	if useFourScreenMirroring {
-		mirroring = FourScreenMirroring
-	} else if useVerticalMirroring {
-		mirroring = VerticalMirroring
+// checkRomSizes rejects headers with chip sizes that don't fit in what is
+// left of the file, before they are allocated. NES 2.0 exponent sizes can
+// go way past any real rom, or overflow.
+func checkRomSizes(reader io.Reader, headers *Header) error {
+	if headers.ProgramRomSize <= 0 || headers.CharacterRomSize < 0 {
+		return ErrInvalidRomFile
 	}
-
-	useBatteryBackedRam := (firstControlByte & 0b10) == 1
-	mapperId := int((secondControlByte & 0b11110000) | (firstControlByte >> 4))
-
-	useTrainer := (firstControlByte & 0b100) == 1
-
-	return &cartridgeHeaders{
-		ProgramBanksQuantity:   programBanksQuantity,
-		ProgramRomSize:         programSize,
-		CharacterBanksQuantity: charactersBanks,
-		CharacterRomSize:       charactersSize,
-		Mirroring:              mirroring,
-		UseBatteryBackedRam:    useBatteryBackedRam,
-		UseCharacterRam:        useCharacterRom,
-		UseTrainer:             useTrainer,
-		RamBanksQuantity:       ramBanksQuantity,
-		MapperId:               mapperId,
-	}, nil
-
+	remaining, ok := reader.(remainingReader)
+	if !ok {
+		return nil
+	}
+	left := remaining.Len()
+	if headers.UseTrainer {
+		left -= trainerSize
+	}
+	if headers.ProgramRomSize > left || headers.CharacterRomSize > left-headers.ProgramRomSize {
+		return ErrInvalidRomFile
+	}
+	return nil
 }
 
-func readRom(reader io.Reader, headers *cartridgeHeaders) (*cartridgeRom, error) {
+func readRom(reader io.Reader, headers *Header) (*Rom, error) {
+	if err := checkRomSizes(reader, headers); err != nil {
+		return nil, err
+	}
 	var trainer []byte
 	if headers.UseTrainer {
-		trainer = make([]byte, 512)
-		_, err := reader.Read(trainer)
-		if err != nil {
+		trainer = make([]byte, trainerSize)
+		if err := readFull(reader, trainer); err != nil {
 			return nil, err
 		}
 	}
 
 	prgRom := make([]byte, headers.ProgramRomSize)
-	n, err := reader.Read(prgRom)
-	if err != nil {
+	if err := readFull(reader, prgRom); err != nil {
 		return nil, err
 	}
 
-	if n != headers.ProgramRomSize {
-		return nil, ErrInvalidRomFile
+	var chrRom = make(CharacterRom, headers.CharacterRomSize)
+	if err := readFull(reader, chrRom); err != nil {
+		return nil, err
 	}
 
-	var chrRomData = make([]byte, headers.CharacterRomSize)
-	var chrRom characterMemory
-	if headers.UseCharacterRam {
-		chrRom = characterRam(chrRomData)
-	} else {
-		n, err = reader.Read(chrRomData)
-		if err != nil {
-			return nil, err
-		}
-		if n != headers.CharacterRomSize {
-			return nil, ErrInvalidRomFile
-		}
-		chrRom = characterRom(chrRomData)
+	// NES 2.0 sizes can be smaller than a bank, or not a multiple of it,
+	// while the boards always map whole banks
+	prgRom = fillBanks(prgRom, prgBankSize)
+	chrRom = fillBanks(chrRom, chrBankSize)
+	headers.ProgramRomSize = len(prgRom)
+	headers.CharacterRomSize = len(chrRom)
+	headers.ProgramBanksQuantity = len(prgRom) / prgBankSize
+	if len(chrRom) > 0 {
+		headers.CharacterBanksQuantity = len(chrRom) / chrBankSize
 	}
 
-	return &cartridgeRom{
+	return &Rom{
 		Character: chrRom,
 		Program:   prgRom,
 		Trainers:  trainer,
 	}, nil
 }
 
+// fillBanks grows data to a multiple of bankSize by repeating it, the way
+// a smaller chip shows up mirrored in a bigger window.
+func fillBanks(data []byte, bankSize int) []byte {
+	if len(data)%bankSize == 0 {
+		return data
+	}
+	filled := make([]byte, (len(data)/bankSize+1)*bankSize)
+	for offset := 0; offset < len(filled); offset += len(data) {
+		copy(filled[offset:], data)
+	}
+	return filled
+}
+
+// allocateRam creates the PRG-RAM and, for boards without CHR-ROM, the
+// CHR-RAM once the header has its final values. Roms with a trainer get
+// PRG-RAM to hold it even when the header asks for none.
+func allocateRam(rom *Rom, headers *Header, state poweron.RamState) {
+	programRamSize := headers.ProgramRamSize + headers.ProgramNvramSize
+	if rom.Trainers != nil && programRamSize == 0 {
+		programRamSize = programRamBankSize
+	}
+	rom.ProgramRam = make([]byte, programRamSize)
+	state.Fill(rom.ProgramRam)
+	if headers.UseCharacterRam {
+		rom.Character = make(CharacterRam, headers.CharacterRamSize+headers.CharacterNvramSize)
+	}
+}
+
+// loadTrainer copies the trainer to $7000-$71FF, where the code the rom
+// was patched with expects it. It goes after the save data, as the trainer
+// is part of the rom and not of the game progress.
+func (c *Cartridge) loadTrainer() {
+	if c.rom.Trainers == nil {
+		return
+	}
+	copy(c.rom.ProgramRam[c.programRamAddress(trainerAddr):], c.rom.Trainers)
+}
+
+// Game returns the game database entry matching the cartridge, if any.
+func (c *Cartridge) Game() (GameInfo, bool) {
+	if c.game == nil {
+		return GameInfo{}, false
+	}
+	return *c.game, true
+}
+
+// Mapper returns the board logic of the cartridge, so boards added with
+// RegisterMapper can be reached through their own methods.
+func (c *Cartridge) Mapper() Mapper {
+	return c.mapper
+}
+
+// Header returns the header the cartridge was loaded with, after the game
+// database corrections.
+func (c *Cartridge) Header() Header {
+	return c.headers
+}
+
+// newFourScreenVram allocates the extra nametable memory of four-screen
+// boards. Boards giving header bit 3 another meaning report a different
+// mirroring at power on and get no memory.
+func newFourScreenVram(headers *Header, mapper Mapper) []byte {
+	if headers.Mirroring != FourScreenMirroring || mapper.Mirroring() != FourScreenMirroring {
+		return nil
+	}
+	return make([]byte, fourScreenVramSize)
+}
+
+// Mirroring returns the current nametable arrangement. Four-screen boards
+// wire every quadrant to its own page, so the mapper setting is ignored.
 func (c *Cartridge) Mirroring() MirroringType {
+	if c.vram != nil {
+		return FourScreenMirroring
+	}
 	return c.mapper.Mirroring()
 }
 
+// NametablePage returns the 1KB page the nametable quadrant of addr is
+// mapped to. Pages 0 and 1 live in the console CIRAM, any other page is
+// served by the cartridge through ReadNametable and WriteNametable.
+func (c *Cartridge) NametablePage(addr uint16) int {
+	if c.vram == nil {
+		if pages, ok := c.mapper.(NametablePageMapper); ok {
+			return pages.NametablePage(addr)
+		}
+	}
+	return c.Mirroring().nametablePage(addr)
+}
+
+// InsertDisk puts the given disk side, counting from zero, in the drive of
+// disk system images.
+func (c *Cartridge) InsertDisk(side int) error {
+	if disk, ok := c.mapper.(DiskMapper); ok {
+		return disk.InsertDisk(side)
+	}
+	return fmt.Errorf("%w: cartridge has no disk", ErrInvalidDiskSide)
+}
+
+// EjectDisk takes the disk out of the drive, or puts it back in when the
+// drive is empty. Cartridges without a disk ignore it.
+func (c *Cartridge) EjectDisk() {
+	if disk, ok := c.mapper.(DiskMapper); ok {
+		disk.EjectDisk()
+	}
+}
+
+// SwitchDiskSide puts the next disk side in the drive. Cartridges without
+// a disk ignore it.
+func (c *Cartridge) SwitchDiskSide() error {
+	if disk, ok := c.mapper.(DiskMapper); ok {
+		return disk.SwitchDiskSide()
+	}
+	return nil
+}
+
+// DiskSides returns how many disk sides the image has, zero for cartridges.
+func (c *Cartridge) DiskSides() int {
+	if disk, ok := c.mapper.(DiskMapper); ok {
+		return disk.DiskSides()
+	}
+	return 0
+}
+
+// AudioSample returns the output of the cartridge sound channels, between
+// 0 and 1, for the boards that have them.
+func (c *Cartridge) AudioSample() float32 {
+	if audio, ok := c.mapper.(AudioMapper); ok {
+		return audio.AudioSample()
+	}
+	return 0
+}
+
+// Reset signals the cartridge that the console reset button was pressed.
+func (c *Cartridge) Reset() {
+	if resettable, ok := c.mapper.(ResettableMapper); ok {
+		resettable.Reset()
+	}
+}
+
+// ClockCPU advances the board logic driven by the CPU clock by one cycle.
+func (c *Cartridge) ClockCPU() {
+	if clocked, ok := c.mapper.(CPUClockedMapper); ok {
+		clocked.ClockCPU()
+	}
+}
+
+// ClockScanline notifies the board that the PPU finished fetching the
+// background of a rendered scanline.
+func (c *Cartridge) ClockScanline() {
+	if counter, ok := c.mapper.(ScanlineMapper); ok {
+		counter.ClockScanline()
+	}
+}
+
+// SnoopPPUAddress shows the board an address the PPU is about to access.
+func (c *Cartridge) SnoopPPUAddress(addr uint16) {
+	if snooper, ok := c.mapper.(PPUAddressMapper); ok {
+		snooper.SnoopPPUAddress(addr)
+	}
+}
+
+// ProgramRomOffset returns where in the program rom addr is mapped. The
+// second result is false for addresses outside of the program rom, and
+// for boards that don't implement ProgramRomMapper.
+func (c *Cartridge) ProgramRomOffset(addr uint16) (int, bool) {
+	if isProgramRamAddr(addr) && len(c.rom.ProgramRam) > 0 {
+		return 0, false
+	}
+	if banks, ok := c.mapper.(ProgramRomMapper); ok {
+		return banks.ProgramRomOffset(addr)
+	}
+	return 0, false
+}
+
+// IRQ reports whether the board is asserting the CPU IRQ line.
+func (c *Cartridge) IRQ() bool {
+	if source, ok := c.mapper.(IRQMapper); ok {
+		return source.IRQ()
+	}
+	return false
+}
+
+// ReadNametable reads a nametable byte from the cartridge when the board is
+// mapping its own memory over the PPU nametable space. The second result is
+// false when the access should go to the console CIRAM instead.
+func (c *Cartridge) ReadNametable(addr uint16) (uint8, bool) {
+	if nametables, ok := c.mapper.(NametableMapper); ok {
+		if data, ok := nametables.ReadNametable(addr); ok {
+			return data, true
+		}
+	}
+	if vramAddr, ok := c.vramAddress(addr); ok {
+		return c.vram[vramAddr], true
+	}
+	return 0, false
+}
+
+// WriteNametable writes a nametable byte to the cartridge, reporting false
+// when the access should go to the console CIRAM instead.
+func (c *Cartridge) WriteNametable(addr uint16, data uint8) bool {
+	if nametables, ok := c.mapper.(NametableMapper); ok {
+		if nametables.WriteNametable(addr, data) {
+			return true
+		}
+	}
+	if vramAddr, ok := c.vramAddress(addr); ok {
+		c.vram[vramAddr] = data
+		return true
+	}
+	return false
+}
+
+func (c *Cartridge) vramAddress(addr uint16) (int, bool) {
+	page := c.NametablePage(addr) - ciramPages
+	if c.vram == nil || page < 0 {
+		return 0, false
+	}
+	return (page*nametablePageSize + int(addr&nametablePageMask)) % len(c.vram), true
+}
+
 func (c *Cartridge) ReadPrgRom(addr uint16) uint8 {
+	if isProgramRamAddr(addr) && len(c.rom.ProgramRam) > 0 {
+		if ram, ok := c.mapper.(ProgramRamMapper); ok && !ram.ProgramRamReadable() {
+			return 0
+		}
+		return c.rom.ProgramRam[c.programRamAddress(addr)]
+	}
 	return c.mapper.ReadPrg(addr)
 }
 
 func (c *Cartridge) WritePrgRom(addr uint16, data uint8) {
+	if isProgramRamAddr(addr) && len(c.rom.ProgramRam) > 0 {
+		if ram, ok := c.mapper.(ProgramRamMapper); ok && !ram.ProgramRamWritable() {
+			return
+		}
+		c.rom.ProgramRam[c.programRamAddress(addr)] = data
+		return
+	}
 	c.mapper.WritePrg(addr, data)
 }
 
+func isProgramRamAddr(addr uint16) bool {
+	return addr >= programRamStart && addr < programRamEnd
+}
+
+func (c *Cartridge) programRamAddress(addr uint16) int {
+	return int(addr-programRamStart) % len(c.rom.ProgramRam)
+}
+
 func (c *Cartridge) ReadChrRom(addr uint16) uint8 {
 	return c.mapper.ReadChr(addr)
 }
@@ -230,3 +598,85 @@ func (c *Cartridge) ReadChrRom(addr uint16) uint8 {
 func (c *Cartridge) WriteChrRom(addr uint16, data uint8) {
 	c.mapper.WriteChr(addr, data)
 }
+
+// Save writes the battery backed memory to the save file next to the rom.
+// Nothing is written when the cartridge has no battery or the data did not
+// change since the last save.
+func (c *Cartridge) Save() error {
+	data := c.SaveData()
+	if data == nil || c.savePath == "" || bytes.Equal(data, c.lastSave) {
+		return nil
+	}
+	if err := os.WriteFile(c.savePath, data, 0o644); err != nil {
+		return err
+	}
+	c.lastSave = data
+	return nil
+}
+
+func (c *Cartridge) loadSave() error {
+	if !c.headers.UseBatteryBackedRam || c.savePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.savePath)
+	if errors.Is(err, os.ErrNotExist) {
+		// nothing is worth saving until the game changes the memory
+		c.lastSave = c.SaveData()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.LoadSaveData(data); err != nil {
+		return err
+	}
+	c.lastSave = data
+	return nil
+}
+
+// SaveData returns a copy of the battery backed memory of the cartridge, or
+// nil when the board keeps nothing across power cycles or has nothing to
+// save yet.
+func (c *Cartridge) SaveData() []byte {
+	if !c.headers.UseBatteryBackedRam {
+		return nil
+	}
+	if persistent, ok := c.mapper.(PersistentMapper); ok {
+		return bytes.Clone(persistent.SaveData())
+	}
+	return bytes.Clone(c.rom.ProgramRam)
+}
+
+// LoadSaveData replaces the battery backed memory of the cartridge with
+// data, usually read from a save file made by another emulator.
+func (c *Cartridge) LoadSaveData(data []byte) error {
+	if !c.headers.UseBatteryBackedRam {
+		return fmt.Errorf("%w: cartridge has no battery", ErrInvalidSaveData)
+	}
+	if persistent, ok := c.mapper.(PersistentMapper); ok {
+		return persistent.LoadSaveData(data)
+	}
+	if len(data) != len(c.rom.ProgramRam) {
+		return fmt.Errorf("%w: save has %d bytes, expected %d", ErrInvalidSaveData, len(data), len(c.rom.ProgramRam))
+	}
+	copy(c.rom.ProgramRam, data)
+	return nil
+}
+
+// MapperState returns the board registers, for boards that can save them.
+func (c *Cartridge) MapperState() ([]byte, error) {
+	stateful, ok := c.mapper.(StatefulMapper)
+	if !ok {
+		return nil, ErrStatelessMapper
+	}
+	return stateful.SaveState()
+}
+
+// LoadMapperState restores the board registers saved by MapperState.
+func (c *Cartridge) LoadMapperState(data []byte) error {
+	stateful, ok := c.mapper.(StatefulMapper)
+	if !ok {
+		return ErrStatelessMapper
+	}
+	return stateful.LoadState(data)
+}
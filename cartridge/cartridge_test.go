@@ -0,0 +1,129 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNametablePage(t *testing.T) {
+	tests := []struct {
+		name      string
+		mirroring MirroringType
+		wantPages [4]int
+	}{
+		{name: "horizontal", mirroring: HorizontalMirroring, wantPages: [4]int{0, 0, 1, 1}},
+		{name: "vertical", mirroring: VerticalMirroring, wantPages: [4]int{0, 1, 0, 1}},
+		{name: "single screen lower", mirroring: SingleScreenLowerMirroring, wantPages: [4]int{0, 0, 0, 0}},
+		{name: "single screen upper", mirroring: SingleScreenUpperMirroring, wantPages: [4]int{1, 1, 1, 1}},
+		{name: "four screen", mirroring: FourScreenMirroring, wantPages: [4]int{0, 1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for quadrant, want := range test.wantPages {
+				addr := 0x2000 + uint16(quadrant)*nametablePageSize + 0x155
+				require.Equal(t, want, test.mirroring.nametablePage(addr))
+				require.Equal(t, want, test.mirroring.nametablePage(addr+0x1000))
+			}
+		})
+	}
+}
+
+func TestFourScreenVram(t *testing.T) {
+	headers := &Header{Mirroring: FourScreenMirroring}
+	mapper := &nrom{mirroring: VerticalMirroring}
+	cart := &Cartridge{mapper: mapper, vram: newFourScreenVram(headers, mapper)}
+	require.Nil(t, cart.vram, "the board overrides the header four-screen bit")
+
+	mapper.mirroring = FourScreenMirroring
+	cart = &Cartridge{mapper: mapper, vram: newFourScreenVram(headers, mapper)}
+	require.Equal(t, FourScreenMirroring, cart.Mirroring())
+
+	_, ok := cart.ReadNametable(0x2400)
+	require.False(t, ok, "the first two pages stay in the console CIRAM")
+
+	require.True(t, cart.WriteNametable(0x2C10, 0x42))
+	data, ok := cart.ReadNametable(0x3C10)
+	require.True(t, ok)
+	require.Equal(t, uint8(0x42), data)
+	data, _ = cart.ReadNametable(0x2810)
+	require.Equal(t, uint8(0), data)
+}
+
+func TestProgramRam(t *testing.T) {
+	rom := &Rom{
+		Program:    make([]byte, prgBankSize),
+		ProgramRam: make([]byte, programRamBankSize),
+	}
+	mapper := newMMC3(rom, &Header{})
+	cart := &Cartridge{
+		headers: Header{UseBatteryBackedRam: true},
+		rom:     rom,
+		mapper:  mapper,
+	}
+
+	cart.WritePrgRom(0x6010, 0x42)
+	require.Equal(t, uint8(0x42), cart.ReadPrgRom(0x6010))
+
+	cart.WritePrgRom(0xA001, mmc3RamEnableBit|mmc3RamProtectBit)
+	cart.WritePrgRom(0x6010, 0x24)
+	require.Equal(t, uint8(0x42), cart.ReadPrgRom(0x6010), "write protected ram keeps its data")
+
+	cart.WritePrgRom(0xA001, 0)
+	require.Equal(t, uint8(0), cart.ReadPrgRom(0x6010), "disabled ram is not readable")
+
+	save := cart.SaveData()
+	require.Equal(t, uint8(0x42), save[0x10])
+	save[0x10] = 0x99
+	require.Equal(t, uint8(0x42), rom.ProgramRam[0x10], "the save data is a copy")
+
+	require.NoError(t, cart.LoadSaveData(save))
+	require.Equal(t, uint8(0x99), rom.ProgramRam[0x10])
+	require.ErrorIs(t, cart.LoadSaveData(save[:10]), ErrInvalidSaveData)
+}
+
+// newBankedTestRom creates a rom with the number of every 8KB PRG bank and
+// 1KB CHR bank written to its first byte, so tests can tell which bank a
+// window is mapped to.
+func newBankedTestRom(prgBanks int, chrBanks int) *Rom {
+	prg := make([]byte, prgBanks*prgBank8kSize)
+	for bank := range prgBanks {
+		prg[bank*prgBank8kSize] = uint8(bank)
+	}
+	chr := make(CharacterRom, chrBanks*chrBank1kSize)
+	for bank := range chrBanks {
+		chr[bank*chrBank1kSize] = uint8(bank)
+	}
+	return &Rom{Program: prg, Character: chr}
+}
+
+// prgBanks returns the 8KB banks mapped at $8000, $A000, $C000 and $E000.
+func prgBanks(m Mapper) []uint8 {
+	banks := make([]uint8, 4)
+	for i := range banks {
+		banks[i] = m.ReadPrg(0x8000 + uint16(i)*prgBank8kSize)
+	}
+	return banks
+}
+
+// chrBanks returns the 1KB banks mapped in the pattern tables.
+func chrBanks(m Mapper) []uint8 {
+	banks := make([]uint8, 8)
+	for i := range banks {
+		banks[i] = m.ReadChr(uint16(i) * chrBank1kSize)
+	}
+	return banks
+}
+
+// mapperWrite is a CPU write to a board register.
+type mapperWrite struct {
+	addr uint16
+	data uint8
+}
+
+func writeMapper(m Mapper, writes []mapperWrite) {
+	for _, write := range writes {
+		m.WritePrg(write.addr, write.data)
+	}
+}
@@ -0,0 +1,241 @@
+package cartridge
+
+const (
+	fdsWaveTableSize       = 64
+	fdsModTableSize        = 64
+	fdsEnvelopeDisableBit  = 0b10000000
+	fdsEnvelopeIncreaseBit = 0b01000000
+	fdsEnvelopeValueMask   = 0b00111111
+	fdsFrequencyHighMask   = 0b00001111
+	fdsEnvelopesHaltBit    = 0b01000000
+	fdsWaveHaltBit         = 0b10000000
+	fdsModHaltBit          = 0b10000000
+	fdsWaveWriteBit        = 0b10000000
+	fdsMasterVolumeMask    = 0b00000011
+	fdsModCounterMask      = 0b01111111
+	fdsModTableValueMask   = 0b00000111
+	fdsMaxGain             = 32
+	fdsModCounterReset     = 4
+	fdsWaveSampleMax       = 63
+	fdsAccumulatorBits     = 16
+)
+
+// fdsMasterVolumes are the output levels selected by $4089, as fractions
+// of the full volume scaled by 30.
+var fdsMasterVolumes = [4]int{30, 20, 15, 12}
+
+// fdsModSteps are the changes applied to the modulation counter by each
+// value of the modulation table. The value 4 resets the counter instead.
+var fdsModSteps = [8]int{0, 1, 2, 4, 0, -4, -2, -1}
+
+// fdsEnvelope is the volume or modulation envelope, which moves its gain
+// one step up or down every time its timer runs out.
+type fdsEnvelope struct {
+	speed    uint8
+	gain     uint8
+	increase bool
+	disabled bool
+	timer    int
+}
+
+func (e *fdsEnvelope) write(data uint8) {
+	e.speed = data & fdsEnvelopeValueMask
+	e.increase = data&fdsEnvelopeIncreaseBit != 0
+	e.disabled = data&fdsEnvelopeDisableBit != 0
+	if e.disabled {
+		e.gain = e.speed
+	}
+	e.timer = 0
+}
+
+func (e *fdsEnvelope) clock(masterSpeed uint8) {
+	if e.disabled {
+		return
+	}
+	e.timer++
+	if e.timer < 8*(int(masterSpeed)+1)*(int(e.speed)+1) {
+		return
+	}
+	e.timer = 0
+	if e.increase && e.gain < fdsMaxGain {
+		e.gain++
+	} else if !e.increase && e.gain > 0 {
+		e.gain--
+	}
+}
+
+// fdsAudio implements the disk system sound channel: a 64 step wavetable
+// whose pitch is bent by a second wavetable, the modulation unit.
+type fdsAudio struct {
+	waveTable       [fdsWaveTableSize]uint8
+	waveWrite       bool
+	waveHalt        bool
+	waveFrequency   uint16
+	waveAccumulator uint32
+	wavePosition    uint8
+	envelopesHalt   bool
+	envelopeSpeed   uint8
+	masterVolume    uint8
+	volume          fdsEnvelope
+	modEnvelope     fdsEnvelope
+	modTable        [fdsModTableSize]uint8
+	modPosition     uint8
+	modFrequency    uint16
+	modHalt         bool
+	modAccumulator  uint32
+	modCounter      int
+	output          float32
+}
+
+func newFDSAudio() *fdsAudio {
+	return &fdsAudio{envelopeSpeed: 0xE8}
+}
+
+func (a *fdsAudio) Read(addr uint16) uint8 {
+	switch {
+	case addr < 0x4080:
+		return a.waveTable[addr-0x4040]
+	case addr == 0x4090:
+		return a.volume.gain
+	case addr == 0x4092:
+		return a.modEnvelope.gain
+	}
+	return 0
+}
+
+func (a *fdsAudio) Write(addr uint16, data uint8) {
+	switch {
+	case addr < 0x4080:
+		if a.waveWrite {
+			a.waveTable[addr-0x4040] = data & fdsEnvelopeValueMask
+		}
+	case addr == 0x4080:
+		a.volume.write(data)
+	case addr == 0x4082:
+		a.waveFrequency = a.waveFrequency&0x0F00 | uint16(data)
+	case addr == 0x4083:
+		a.waveFrequency = a.waveFrequency&0x00FF | uint16(data&fdsFrequencyHighMask)<<8
+		a.envelopesHalt = data&fdsEnvelopesHaltBit != 0
+		a.waveHalt = data&fdsWaveHaltBit != 0
+		if a.waveHalt {
+			a.waveAccumulator = 0
+			a.wavePosition = 0
+		}
+		if a.envelopesHalt {
+			a.volume.timer = 0
+			a.modEnvelope.timer = 0
+		}
+	case addr == 0x4084:
+		a.modEnvelope.write(data)
+	case addr == 0x4085:
+		a.modCounter = signExtendModCounter(data & fdsModCounterMask)
+	case addr == 0x4086:
+		a.modFrequency = a.modFrequency&0x0F00 | uint16(data)
+	case addr == 0x4087:
+		a.modFrequency = a.modFrequency&0x00FF | uint16(data&fdsFrequencyHighMask)<<8
+		a.modHalt = data&fdsModHaltBit != 0
+		if a.modHalt {
+			a.modAccumulator = 0
+		}
+	case addr == 0x4088:
+		// the table can only be written while the unit is halted, and
+		// every value takes two steps of the 64 step table
+		if a.modHalt {
+			a.modTable[a.modPosition] = data & fdsModTableValueMask
+			a.modTable[(a.modPosition+1)%fdsModTableSize] = data & fdsModTableValueMask
+			a.modPosition = (a.modPosition + 2) % fdsModTableSize
+		}
+	case addr == 0x4089:
+		a.waveWrite = data&fdsWaveWriteBit != 0
+		a.masterVolume = data & fdsMasterVolumeMask
+	case addr == 0x408A:
+		a.envelopeSpeed = data
+	}
+}
+
+func signExtendModCounter(value uint8) int {
+	if value&0x40 != 0 {
+		return int(value) - 0x80
+	}
+	return int(value)
+}
+
+// Clock advances the channel by one CPU cycle.
+func (a *fdsAudio) Clock() {
+	if !a.envelopesHalt && !a.waveHalt && a.envelopeSpeed != 0 {
+		a.volume.clock(a.envelopeSpeed)
+		a.modEnvelope.clock(a.envelopeSpeed)
+	}
+
+	if !a.modHalt && a.modFrequency != 0 {
+		a.modAccumulator += uint32(a.modFrequency)
+		if a.modAccumulator >= 1<<fdsAccumulatorBits {
+			a.modAccumulator -= 1 << fdsAccumulatorBits
+			a.stepModulation()
+		}
+	}
+
+	if a.waveHalt || a.waveWrite {
+		return
+	}
+	pitch := a.modulatedPitch()
+	if pitch <= 0 {
+		return
+	}
+	a.waveAccumulator += uint32(pitch)
+	if a.waveAccumulator >= 1<<fdsAccumulatorBits {
+		a.waveAccumulator -= 1 << fdsAccumulatorBits
+		a.wavePosition = (a.wavePosition + 1) % fdsWaveTableSize
+		a.updateOutput()
+	}
+}
+
+func (a *fdsAudio) stepModulation() {
+	value := a.modTable[a.modPosition]
+	a.modPosition = (a.modPosition + 1) % fdsModTableSize
+	if value == fdsModCounterReset {
+		a.modCounter = 0
+		return
+	}
+	a.modCounter += fdsModSteps[value]
+	// the counter is 7 bits wide and wraps around
+	a.modCounter = signExtendModCounter(uint8(a.modCounter) & fdsModCounterMask)
+}
+
+// modulatedPitch applies the modulation unit to the wave frequency, with
+// the rounding of the original hardware.
+func (a *fdsAudio) modulatedPitch() int {
+	temp := a.modCounter * int(a.modEnvelope.gain)
+	remainder := temp & 0x0F
+	temp >>= 4
+	if remainder > 0 && temp&0x80 == 0 {
+		if a.modCounter < 0 {
+			temp--
+		} else {
+			temp += 2
+		}
+	}
+	if temp >= 192 {
+		temp -= 256
+	} else if temp < -64 {
+		temp += 256
+	}
+	temp *= int(a.waveFrequency)
+	remainder = temp & 0x3F
+	temp >>= 6
+	if remainder >= 32 {
+		temp++
+	}
+	return int(a.waveFrequency) + temp
+}
+
+func (a *fdsAudio) updateOutput() {
+	gain := min(int(a.volume.gain), fdsMaxGain)
+	level := int(a.waveTable[a.wavePosition]) * gain * fdsMasterVolumes[a.masterVolume]
+	a.output = float32(level) / float32(fdsWaveSampleMax*fdsMaxGain*fdsMasterVolumes[0])
+}
+
+// Output returns the current level of the channel, between 0 and 1.
+func (a *fdsAudio) Output() float32 {
+	return a.output
+}
@@ -0,0 +1,91 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFDSAudioWaveTable(t *testing.T) {
+	audio := newFDSAudio()
+	audio.Write(0x4089, fdsWaveWriteBit)
+	for i := range fdsWaveTableSize {
+		audio.Write(0x4040+uint16(i), uint8(i)|0b11000000)
+	}
+	require.Equal(t, uint8(5), audio.Read(0x4045), "samples are 6 bits wide")
+
+	audio.Write(0x4089, 0)
+	audio.Write(0x4045, 0)
+	require.Equal(t, uint8(5), audio.Read(0x4045), "the table is write protected")
+
+	audio.Write(0x4080, fdsEnvelopeDisableBit|fdsMaxGain)
+	require.Equal(t, uint8(fdsMaxGain), audio.Read(0x4090))
+	audio.Write(0x4082, 0xFF)
+	audio.Write(0x4083, 0x0F)
+
+	// the accumulator overflows about every 16 cycles at this frequency
+	for range 17 * 3 {
+		audio.Clock()
+	}
+	require.Equal(t, uint8(3), audio.wavePosition)
+	require.InDelta(t, 3.0/fdsWaveSampleMax, audio.Output(), 1e-6)
+
+	audio.Write(0x4083, fdsWaveHaltBit)
+	audio.Clock()
+	require.Zero(t, audio.wavePosition, "halting resets the wave position")
+}
+
+func TestFDSAudioVolumeEnvelope(t *testing.T) {
+	audio := newFDSAudio()
+	audio.Write(0x408A, 1)
+	audio.Write(0x4080, fdsEnvelopeIncreaseBit)
+	audio.Write(0x4083, 0)
+
+	// 8 * (master speed + 1) * (envelope speed + 1) cycles per step
+	for range 2 * 16 {
+		audio.Clock()
+	}
+	require.Equal(t, uint8(2), audio.Read(0x4090))
+
+	audio.Write(0x4083, fdsEnvelopesHaltBit)
+	for range 16 {
+		audio.Clock()
+	}
+	require.Equal(t, uint8(2), audio.Read(0x4090))
+}
+
+func TestFDSAudioModulation(t *testing.T) {
+	audio := newFDSAudio()
+	audio.Write(0x4082, 0x00)
+	audio.Write(0x4083, 0x01)
+	audio.Write(0x4084, fdsEnvelopeDisableBit|16)
+	require.Equal(t, uint8(16), audio.Read(0x4092))
+	require.Equal(t, 0x100, audio.modulatedPitch(), "a zero counter leaves the pitch alone")
+
+	audio.Write(0x4087, fdsModHaltBit)
+	audio.Write(0x4088, 1)
+	audio.Write(0x4088, fdsModCounterReset)
+	require.Equal(t, []uint8{1, 1, fdsModCounterReset, fdsModCounterReset}, audio.modTable[:4])
+
+	audio.modPosition = 0
+	audio.stepModulation()
+	require.Equal(t, 1, audio.modCounter)
+	require.Equal(t, 0x104, audio.modulatedPitch())
+	audio.stepModulation()
+	audio.stepModulation()
+	require.Zero(t, audio.modCounter, "the value 4 resets the counter")
+
+	audio.Write(0x4085, 0x7F)
+	require.Equal(t, -1, audio.modCounter, "the counter is a signed 7 bit value")
+}
+
+func TestFDSSoundRegistersEnable(t *testing.T) {
+	m := newINES20(&Rom{Program: make([]byte, fdsBiosSize)}, &Header{}).(*fdsRamAdapter)
+	m.WritePrg(0x4080, fdsEnvelopeDisableBit|7)
+	require.Zero(t, m.ReadPrg(0x4090), "the sound registers start disabled")
+
+	m.WritePrg(0x4023, fdsSoundEnableBit)
+	m.WritePrg(0x4080, fdsEnvelopeDisableBit|7)
+	require.Equal(t, uint8(7), m.ReadPrg(0x4090))
+	require.Implements(t, (*AudioMapper)(nil), m)
+}
@@ -0,0 +1,173 @@
+package cartridge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+const (
+	// fdsMapperId is the mapper number NES 2.0 reserves for the disk system
+	fdsMapperId     = 20
+	fdsHeaderSize   = 16
+	fdsSideSize     = 65500
+	fdsBiosSize     = 8 * 1024
+	fdsSidesIndex   = 4
+	fdsRamSize      = 32 * 1024
+	fdsChrRamSize   = 8 * 1024
+	fdsBlockCRCSize = 2
+	// fdsLeadInGap and fdsBlockGap are the gaps, in bytes, the drive spins
+	// through before the first block and between blocks
+	fdsLeadInGap = 28300 / 8
+	fdsBlockGap  = 976 / 8
+	// fdsGapEnd is the mark the drive looks for to find the start of a block
+	fdsGapEnd = 0x80
+	// fdsBiosFileName is the usual name of the bios dump, looked for next to
+	// disk images when no bios is given
+	fdsBiosFileName = "disksys.rom"
+)
+
+const (
+	fdsBlockDiskInfo = iota + 1
+	fdsBlockFileAmount
+	fdsBlockFileHeader
+	fdsBlockFileData
+)
+
+const (
+	fdsDiskInfoBlockSize   = 56
+	fdsFileAmountBlockSize = 2
+	fdsFileHeaderBlockSize = 16
+	// fdsFileSizeIndex is where the file size is in the file header block
+	fdsFileSizeIndex = 13
+)
+
+var (
+	fdsMagic    = []byte{'F', 'D', 'S', 0x1A}
+	fdsDiskInfo = []byte("\x01*NINTENDO-HVC*")
+)
+
+var ErrMissingFDSBios = errors.New("missing disk system bios")
+var ErrInvalidDiskSide = errors.New("invalid disk side")
+
+func isFDSImage(data []byte) bool {
+	return bytes.HasPrefix(data, fdsMagic) || bytes.HasPrefix(data, fdsDiskInfo)
+}
+
+// readFDS reads a disk image in the fwNES format, with or without its 16
+// byte header. The disk sides are kept as in the file, the drive adds the
+// gaps between blocks when it loads them.
+func readFDS(data []byte, bios []byte) (*Header, *Rom, error) {
+	if bios == nil {
+		return nil, nil, ErrMissingFDSBios
+	}
+	if len(bios) != fdsBiosSize {
+		return nil, nil, fmt.Errorf("%w: disk system bios has %d bytes, expected %d", ErrInvalidRomFile, len(bios), fdsBiosSize)
+	}
+	if bytes.HasPrefix(data, fdsMagic) {
+		if len(data) < fdsHeaderSize {
+			return nil, nil, ErrInvalidRomFile
+		}
+		data = data[fdsHeaderSize:]
+	}
+	sides := len(data) / fdsSideSize
+	if sides == 0 || len(data)%fdsSideSize != 0 {
+		return nil, nil, fmt.Errorf("%w: disk image has %d bytes, expected a multiple of %d", ErrInvalidRomFile, len(data), fdsSideSize)
+	}
+
+	headers := &Header{
+		ProgramRomSize:         fdsBiosSize,
+		ProgramBanksQuantity:   1,
+		CharacterBanksQuantity: 1,
+		CharacterRamSize:       fdsChrRamSize,
+		UseCharacterRam:        true,
+		// the disk is rewritable, so its changes are saved like battery
+		// backed memory
+		UseBatteryBackedRam: true,
+		Mirroring:           HorizontalMirroring,
+		SolderedMirroring:   HorizontalMirroring,
+		MapperId:            fdsMapperId,
+	}
+	rom := &Rom{
+		Program: append([]byte{}, bios...),
+		Disk:    append([]byte{}, data...),
+	}
+	return headers, rom, nil
+}
+
+// fdsBlockSize returns the size of a block given its type, or zero past
+// the last block of the side. File data blocks take their size from the
+// file header block before them.
+func fdsBlockSize(blockType uint8, fileSize int) int {
+	switch blockType {
+	case fdsBlockDiskInfo:
+		return fdsDiskInfoBlockSize
+	case fdsBlockFileAmount:
+		return fdsFileAmountBlockSize
+	case fdsBlockFileHeader:
+		return fdsFileHeaderBlockSize
+	case fdsBlockFileData:
+		return fileSize + 1
+	}
+	return 0
+}
+
+func fdsFileSize(fileHeader []byte) int {
+	return int(fileHeader[fdsFileSizeIndex]) | int(fileHeader[fdsFileSizeIndex+1])<<8
+}
+
+// addFDSGaps lays a disk side out the way the drive reads it, with a gap
+// and a gap end mark before each block and a CRC after it. The CRC is only
+// a placeholder, since reads don't check it, and the RAM adapter computes
+// the real one when the bios writes a block.
+func addFDSGaps(side []byte) []byte {
+	gapped := make([]byte, fdsLeadInGap, fdsLeadInGap+fdsSideSize*2)
+	fileSize := 0
+	for pos := 0; pos < len(side); {
+		size := fdsBlockSize(side[pos], fileSize)
+		if size == 0 || pos+size > len(side) {
+			break
+		}
+		block := side[pos : pos+size]
+		if block[0] == fdsBlockFileHeader {
+			fileSize = fdsFileSize(block)
+		}
+		gapped = append(gapped, fdsGapEnd)
+		gapped = append(gapped, block...)
+		gapped = append(gapped, 0x4D, 0x62)
+		gapped = append(gapped, make([]byte, fdsBlockGap)...)
+		pos += size
+	}
+	if len(gapped) < fdsSideSize {
+		gapped = append(gapped, make([]byte, fdsSideSize-len(gapped))...)
+	}
+	return gapped
+}
+
+// removeFDSGaps undoes addFDSGaps, returning the side in the fwNES layout
+// after the bios wrote to it.
+func removeFDSGaps(gapped []byte) []byte {
+	side := make([]byte, 0, fdsSideSize)
+	fileSize := 0
+	pos := 0
+	for {
+		for pos < len(gapped) && gapped[pos] != fdsGapEnd {
+			pos++
+		}
+		pos++
+		if pos >= len(gapped) {
+			break
+		}
+		size := fdsBlockSize(gapped[pos], fileSize)
+		if size == 0 || pos+size > len(gapped) || len(side)+size > fdsSideSize {
+			break
+		}
+		block := gapped[pos : pos+size]
+		if block[0] == fdsBlockFileHeader {
+			fileSize = fdsFileSize(block)
+		}
+		side = append(side, block...)
+		pos += size + fdsBlockCRCSize
+	}
+	return append(side, make([]byte, fdsSideSize-len(side))...)
+}
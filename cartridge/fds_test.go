@@ -0,0 +1,97 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskSide() []byte {
+	side := make([]byte, fdsDiskInfoBlockSize)
+	copy(side, fdsDiskInfo)
+	side = append(side, fdsBlockFileAmount, 1)
+	fileHeader := make([]byte, fdsFileHeaderBlockSize)
+	fileHeader[0] = fdsBlockFileHeader
+	fileHeader[fdsFileSizeIndex] = 4
+	side = append(side, fileHeader...)
+	side = append(side, fdsBlockFileData, 1, 2, 3, 4)
+	return append(side, make([]byte, fdsSideSize-len(side))...)
+}
+
+func newTestDiskImage(sides int) []byte {
+	image := append([]byte{}, fdsMagic...)
+	image = append(image, uint8(sides))
+	image = append(image, make([]byte, fdsHeaderSize-len(image))...)
+	for range sides {
+		image = append(image, newTestDiskSide()...)
+	}
+	return image
+}
+
+func TestFDSGaps(t *testing.T) {
+	side := newTestDiskSide()
+	gapped := addFDSGaps(side)
+
+	require.Equal(t, uint8(fdsGapEnd), gapped[fdsLeadInGap])
+	require.Equal(t, fdsDiskInfo, gapped[fdsLeadInGap+1:fdsLeadInGap+1+len(fdsDiskInfo)])
+	require.Equal(t, side, removeFDSGaps(gapped))
+}
+
+func TestReadFDS(t *testing.T) {
+	bios := make([]byte, fdsBiosSize)
+	bios[len(bios)-1] = 0xE0
+
+	tests := []struct {
+		name      string
+		data      []byte
+		options   []Option
+		wantSides int
+		wantErr   error
+	}{
+		{name: "two sides", data: newTestDiskImage(2), options: []Option{WithFDSBios(bios)}, wantSides: 2},
+		{name: "headerless", data: newTestDiskImage(1)[fdsHeaderSize:], options: []Option{WithFDSBios(bios)}, wantSides: 1},
+		{name: "missing bios", data: newTestDiskImage(1), wantErr: ErrMissingFDSBios},
+		{name: "short bios", data: newTestDiskImage(1), options: []Option{WithFDSBios(bios[1:])}, wantErr: ErrInvalidRomFile},
+		{name: "truncated side", data: newTestDiskImage(1)[:fdsSideSize], options: []Option{WithFDSBios(bios)}, wantErr: ErrInvalidRomFile},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cart, err := FromBytes(test.data, test.options...)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantSides, cart.DiskSides())
+			require.Equal(t, uint8(0xE0), cart.ReadPrgRom(0xFFFF))
+			require.ErrorIs(t, cart.InsertDisk(test.wantSides), ErrInvalidDiskSide)
+			require.NoError(t, cart.SwitchDiskSide())
+		})
+	}
+}
+
+func TestFDSSaveData(t *testing.T) {
+	bios := make([]byte, fdsBiosSize)
+	image := newTestDiskImage(2)
+	cart, err := FromBytes(image, WithFDSBios(bios))
+	require.NoError(t, err)
+	// an untouched disk saves as an empty patch
+	require.Equal(t, []byte("PATCHEOF"), cart.SaveData())
+
+	// overwrite the first byte of the file data on the second side
+	adapter := cart.mapper.(*fdsRamAdapter)
+	dataBlock := fdsLeadInGap + 3*(fdsBlockGap+fdsBlockCRCSize+1) +
+		fdsDiskInfoBlockSize + fdsFileAmountBlockSize + fdsFileHeaderBlockSize
+	require.Equal(t, uint8(fdsBlockFileData), adapter.sides[1][dataBlock+1])
+	adapter.sides[1][dataBlock+2] = 0xAA
+
+	save := cart.SaveData()
+	require.NotEqual(t, []byte("PATCHEOF"), save)
+
+	loaded, err := FromBytes(image, WithFDSBios(bios))
+	require.NoError(t, err)
+	require.NoError(t, loaded.LoadSaveData(save))
+	require.Equal(t, save, loaded.SaveData())
+	require.Equal(t, uint8(0xAA), loaded.mapper.(*fdsRamAdapter).sides[1][dataBlock+2])
+}
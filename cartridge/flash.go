@@ -0,0 +1,119 @@
+package cartridge
+
+const (
+	flashManufacturerId   uint8 = 0xBF
+	flashDeviceId         uint8 = 0xB7
+	flashSectorSize             = 4 * 1024
+	flashCommandMask            = 0x7FFF
+	flashFirstUnlockAddr        = 0x5555
+	flashSecondUnlockAddr       = 0x2AAA
+)
+
+const (
+	flashCommandErase           uint8 = 0x80
+	flashCommandSoftwareIdEnter uint8 = 0x90
+	flashCommandByteProgram     uint8 = 0xA0
+	flashCommandSoftwareIdExit  uint8 = 0xF0
+	flashCommandSectorErase     uint8 = 0x30
+	flashCommandChipErase       uint8 = 0x10
+)
+
+type flashState uint8
+
+const (
+	flashStateRead flashState = iota
+	flashStateFirstUnlock
+	flashStateSecondUnlock
+	flashStateByteProgram
+	flashStateEraseFirstUnlock
+	flashStateEraseSecondUnlock
+	flashStateEraseCommand
+)
+
+// sst39sf040 emulates the command state machine of the SST39SF040 flash
+// chip. Programming and erasing complete instantly, so the status polling
+// done by games always reads the final value.
+type sst39sf040 struct {
+	data       []byte
+	state      flashState
+	softwareId bool
+	modified   bool
+}
+
+func newSST39SF040(data []byte) *sst39sf040 {
+	return &sst39sf040{data: data}
+}
+
+func (f *sst39sf040) Read(addr int) uint8 {
+	if f.softwareId {
+		if addr&0b1 == 0 {
+			return flashManufacturerId
+		}
+		return flashDeviceId
+	}
+	return f.data[addr%len(f.data)]
+}
+
+func (f *sst39sf040) Write(addr int, data uint8) {
+	command := addr & flashCommandMask
+	switch f.state {
+	case flashStateRead:
+		if command == flashFirstUnlockAddr && data == 0xAA {
+			f.state = flashStateFirstUnlock
+		} else if data == flashCommandSoftwareIdExit {
+			f.softwareId = false
+		}
+	case flashStateFirstUnlock:
+		f.state = flashStateRead
+		if command == flashSecondUnlockAddr && data == 0x55 {
+			f.state = flashStateSecondUnlock
+		}
+	case flashStateSecondUnlock:
+		f.state = flashStateRead
+		if command != flashFirstUnlockAddr {
+			return
+		}
+		switch data {
+		case flashCommandErase:
+			f.state = flashStateEraseFirstUnlock
+		case flashCommandSoftwareIdEnter:
+			f.softwareId = true
+		case flashCommandByteProgram:
+			f.state = flashStateByteProgram
+		case flashCommandSoftwareIdExit:
+			f.softwareId = false
+		}
+	case flashStateByteProgram:
+		f.state = flashStateRead
+		addr %= len(f.data)
+		// programming can only clear bits, erasing is the only way to set them
+		f.data[addr] &= data
+		f.modified = true
+	case flashStateEraseFirstUnlock:
+		f.state = flashStateRead
+		if command == flashFirstUnlockAddr && data == 0xAA {
+			f.state = flashStateEraseSecondUnlock
+		}
+	case flashStateEraseSecondUnlock:
+		f.state = flashStateRead
+		if command == flashSecondUnlockAddr && data == 0x55 {
+			f.state = flashStateEraseCommand
+		}
+	case flashStateEraseCommand:
+		f.state = flashStateRead
+		if data == flashCommandSectorErase {
+			start := (addr % len(f.data)) &^ (flashSectorSize - 1)
+			f.erase(start, start+flashSectorSize)
+		} else if data == flashCommandChipErase && command == flashFirstUnlockAddr {
+			f.erase(0, len(f.data))
+		}
+	}
+}
+
+func (f *sst39sf040) erase(start int, end int) {
+	end = min(end, len(f.data))
+	for i := start; i < end; i++ {
+		f.data[i] = 0xFF
+	}
+	f.modified = true
+}
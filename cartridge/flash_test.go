@@ -0,0 +1,86 @@
+package cartridge
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFlashCommand(f *sst39sf040, command uint8) {
+	f.Write(0x5555, 0xAA)
+	f.Write(0x2AAA, 0x55)
+	f.Write(0x5555, command)
+}
+
+func TestFlashByteProgram(t *testing.T) {
+	flash := newSST39SF040([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+
+	writeFlashCommand(flash, flashCommandByteProgram)
+	flash.Write(2, 0b10101010)
+	require.Equal(t, uint8(0b10101010), flash.Read(2))
+	require.True(t, flash.modified)
+
+	writeFlashCommand(flash, flashCommandByteProgram)
+	flash.Write(2, 0b11110000)
+	require.Equal(t, uint8(0b10100000), flash.Read(2))
+}
+
+func TestFlashIgnoresWritesWithoutUnlock(t *testing.T) {
+	flash := newSST39SF040([]byte{0xFF, 0xFF})
+	flash.Write(0, 0x00)
+	require.Equal(t, uint8(0xFF), flash.Read(0))
+	require.False(t, flash.modified)
+}
+
+func TestFlashSoftwareId(t *testing.T) {
+	flash := newSST39SF040(make([]byte, 4))
+
+	writeFlashCommand(flash, flashCommandSoftwareIdEnter)
+	require.Equal(t, flashManufacturerId, flash.Read(0))
+	require.Equal(t, flashDeviceId, flash.Read(1))
+
+	flash.Write(0, flashCommandSoftwareIdExit)
+	require.Equal(t, uint8(0), flash.Read(0))
+}
+
+func TestFlashSectorErase(t *testing.T) {
+	flash := newSST39SF040(make([]byte, 3*flashSectorSize))
+
+	writeFlashCommand(flash, flashCommandErase)
+	flash.Write(0x5555, 0xAA)
+	flash.Write(0x2AAA, 0x55)
+	flash.Write(flashSectorSize+10, flashCommandSectorErase)
+
+	require.Equal(t, uint8(0x00), flash.Read(flashSectorSize-1))
+	require.Equal(t, uint8(0xFF), flash.Read(flashSectorSize))
+	require.Equal(t, uint8(0xFF), flash.Read(2*flashSectorSize-1))
+	require.Equal(t, uint8(0x00), flash.Read(2*flashSectorSize))
+}
+
+func TestUnrom512SavesProgrammedFlash(t *testing.T) {
+	rom := &Rom{Program: bytes.Repeat([]byte{0xFF}, 4*unrom512FlashBankSize)}
+	headers := &Header{UseBatteryBackedRam: true, ProgramBanksQuantity: 4}
+	m := newINES30(rom, headers).(*unrom512)
+	savePath := filepath.Join(t.TempDir(), "game.sav")
+	cart := &Cartridge{headers: *headers, rom: rom, mapper: m, savePath: savePath}
+
+	require.Nil(t, cart.SaveData(), "an untouched flash matches the rom")
+	require.NoError(t, cart.Save())
+	require.NoFileExists(t, savePath)
+
+	writeFlashCommand(m.flash, flashCommandByteProgram)
+	m.flash.Write(0x10, 0x42)
+	require.NoError(t, cart.Save())
+	save, err := os.ReadFile(savePath)
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x42), save[0x10])
+
+	rom = &Rom{Program: bytes.Repeat([]byte{0xFF}, 4*unrom512FlashBankSize)}
+	loaded := &Cartridge{headers: *headers, rom: rom, mapper: newINES30(rom, headers), savePath: savePath}
+	require.NoError(t, loaded.loadSave())
+	require.Equal(t, uint8(0x42), rom.Program[0x10])
+	require.Equal(t, save, loaded.SaveData(), "an imported save is kept for the next session")
+}
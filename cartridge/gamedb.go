@@ -0,0 +1,176 @@
+package cartridge
+
+import (
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:generate go run ../cmd/gamedb -in nes20db.xml -out gamedb.xml
+
+//go:embed gamedb.xml
+var gameDatabaseXML []byte
+
+var ErrInvalidGameDatabase = errors.New("invalid game database")
+
+// GameInfo describes the game database entry a cartridge matched.
+type GameInfo struct {
+	Title string
+	Board string
+}
+
+type gameDatabase map[uint32][]gameEntry
+
+type gameEntry struct {
+	Name     string       `xml:"name,attr"`
+	Rom      gameRom      `xml:"rom"`
+	Pcb      gamePcb      `xml:"pcb"`
+	PrgRam   *gameMemory  `xml:"prgram"`
+	PrgNvram *gameMemory  `xml:"prgnvram"`
+	ChrRam   *gameMemory  `xml:"chrram"`
+	ChrNvram *gameMemory  `xml:"chrnvram"`
+	Console  *gameConsole `xml:"console"`
+}
+
+type gameRom struct {
+	Size  int    `xml:"size,attr"`
+	CRC32 string `xml:"crc32,attr"`
+	SHA1  string `xml:"sha1,attr"`
+}
+
+type gamePcb struct {
+	Mapper    int    `xml:"mapper,attr"`
+	Submapper int    `xml:"submapper,attr"`
+	Mirroring string `xml:"mirroring,attr"`
+	Battery   int    `xml:"battery,attr"`
+	Board     string `xml:"board,attr"`
+}
+
+type gameMemory struct {
+	Size int `xml:"size,attr"`
+}
+
+type gameConsole struct {
+	Type   int `xml:"type,attr"`
+	Region int `xml:"region,attr"`
+}
+
+var loadGameDatabase = sync.OnceValues(func() (gameDatabase, error) {
+	return parseGameDatabase(gameDatabaseXML)
+})
+
+func parseGameDatabase(data []byte) (gameDatabase, error) {
+	var document struct {
+		Games []gameEntry `xml:"game"`
+	}
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGameDatabase, err)
+	}
+	db := make(gameDatabase, len(document.Games))
+	for _, game := range document.Games {
+		crc, err := strconv.ParseUint(game.Rom.CRC32, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: game %q has crc32 %q", ErrInvalidGameDatabase, game.Name, game.Rom.CRC32)
+		}
+		db[uint32(crc)] = append(db[uint32(crc)], game)
+	}
+	return db, nil
+}
+
+// find returns the entry matching the rom contents, checking the SHA-1 too
+// when the entry has one, since CRC32 collisions do happen between dumps.
+func (db gameDatabase) find(rom *Rom) *gameEntry {
+	crc := crc32.NewIEEE()
+	sha := sha1.New()
+	for _, data := range [][]byte{rom.Program, romCharacterData(rom)} {
+		crc.Write(data)
+		sha.Write(data)
+	}
+	sum := hex.EncodeToString(sha.Sum(nil))
+	for _, game := range db[crc.Sum32()] {
+		if game.Rom.SHA1 == "" || strings.EqualFold(game.Rom.SHA1, sum) {
+			return &game
+		}
+	}
+	return nil
+}
+
+func romCharacterData(rom *Rom) []byte {
+	if chr, ok := rom.Character.(CharacterRom); ok {
+		return chr
+	}
+	return nil
+}
+
+// apply overrides the header fields the entry knows about.
+func (game *gameEntry) apply(headers *Header) {
+	headers.MapperId = game.Pcb.Mapper
+	headers.Submapper = game.Pcb.Submapper
+	headers.UseBatteryBackedRam = game.Pcb.Battery != 0
+	switch game.Pcb.Mirroring {
+	case "H":
+		headers.Mirroring = HorizontalMirroring
+		headers.SolderedMirroring = HorizontalMirroring
+		headers.AlternativeNametables = false
+	case "V":
+		headers.Mirroring = VerticalMirroring
+		headers.SolderedMirroring = VerticalMirroring
+		headers.AlternativeNametables = false
+	case "4":
+		headers.Mirroring = FourScreenMirroring
+		headers.AlternativeNametables = true
+	}
+
+	headers.ProgramRamSize = gameMemorySize(game.PrgRam)
+	headers.ProgramNvramSize = gameMemorySize(game.PrgNvram)
+	if headers.UseCharacterRam && (game.ChrRam != nil || game.ChrNvram != nil) {
+		headers.CharacterRamSize = gameMemorySize(game.ChrRam)
+		headers.CharacterNvramSize = gameMemorySize(game.ChrNvram)
+	}
+	if game.Console != nil {
+		headers.ConsoleType = ConsoleType(game.Console.Type)
+		headers.Timing = TimingMode(game.Console.Region)
+	}
+}
+
+func gameMemorySize(memory *gameMemory) int {
+	if memory == nil {
+		return 0
+	}
+	return memory.Size
+}
+
+// applyGameDatabase corrects headers with the embedded database entry
+// matching the rom, returning nil when the rom is not in it.
+func applyGameDatabase(headers *Header, rom *Rom) (*GameInfo, error) {
+	db, err := loadGameDatabase()
+	if err != nil {
+		return nil, err
+	}
+	game := db.find(rom)
+	if game == nil {
+		return nil, nil
+	}
+	game.apply(headers)
+	return &GameInfo{Title: game.Name, Board: game.Pcb.board()}, nil
+}
+
+// board names the board of the pcb. The NES 2.0 database identifies boards
+// by their mapper and submapper only, so these name it unless the entry has
+// a board of its own.
+func (pcb *gamePcb) board() string {
+	if pcb.Board != "" {
+		return pcb.Board
+	}
+	if pcb.Submapper != 0 {
+		return fmt.Sprintf("mapper %d, submapper %d", pcb.Mapper, pcb.Submapper)
+	}
+	return fmt.Sprintf("mapper %d", pcb.Mapper)
+}
@@ -0,0 +1,120 @@
+package cartridge
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedGameDatabase(t *testing.T) {
+	_, err := loadGameDatabase()
+	require.NoError(t, err)
+}
+
+func TestGameDatabaseOverridesHeader(t *testing.T) {
+	rom := &Rom{
+		Program:   []byte{1, 2, 3, 4},
+		Character: CharacterRom{5, 6},
+	}
+	contents := []byte{1, 2, 3, 4, 5, 6}
+	sha := sha1.Sum(contents)
+
+	tests := []struct {
+		name     string
+		sha1     string
+		wantGame bool
+	}{
+		{name: "matching sha1", sha1: hex.EncodeToString(sha[:]), wantGame: true},
+		{name: "crc32 only", sha1: "", wantGame: true},
+		{name: "crc32 collision", sha1: "0000000000000000000000000000000000000000", wantGame: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, err := parseGameDatabase([]byte(fmt.Sprintf(`
+				<nes20db>
+					<game name="Test Game">
+						<rom size="6" crc32="%08X" sha1="%s" />
+						<pcb mapper="4" submapper="1" mirroring="4" battery="1" board="NES-TVROM" />
+						<prgnvram size="8192" />
+						<console type="0" region="1" />
+					</game>
+				</nes20db>`, crc32.ChecksumIEEE(contents), test.sha1)))
+			require.NoError(t, err)
+
+			game := db.find(rom)
+			if !test.wantGame {
+				require.Nil(t, game)
+				return
+			}
+			require.NotNil(t, game)
+			require.Equal(t, "NES-TVROM", game.Pcb.Board)
+
+			headers := &Header{MapperId: 1, Mirroring: VerticalMirroring, ProgramRamSize: programRamBankSize}
+			game.apply(headers)
+			require.Equal(t, Header{
+				MapperId:              4,
+				Submapper:             1,
+				Mirroring:             FourScreenMirroring,
+				AlternativeNametables: true,
+				UseBatteryBackedRam:   true,
+				ProgramNvramSize:      8192,
+				Timing:                TimingPAL,
+			}, *headers)
+		})
+	}
+}
+
+func TestGameBoard(t *testing.T) {
+	tests := []struct {
+		pcb  gamePcb
+		want string
+	}{
+		{pcb: gamePcb{Mapper: 4, Submapper: 1, Board: "NES-TVROM"}, want: "NES-TVROM"},
+		{pcb: gamePcb{Mapper: 2}, want: "mapper 2"},
+		{pcb: gamePcb{Mapper: 4, Submapper: 1}, want: "mapper 4, submapper 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			require.Equal(t, test.want, test.pcb.board())
+		})
+	}
+}
+
+func TestInvalidGameDatabase(t *testing.T) {
+	_, err := parseGameDatabase([]byte(`<nes20db><game><rom crc32="xyz" /></game></nes20db>`))
+	require.ErrorIs(t, err, ErrInvalidGameDatabase)
+}
+
+func TestLoadingCorrectsHeader(t *testing.T) {
+	data := newTestRom(0, 1)
+	db, err := parseGameDatabase([]byte(fmt.Sprintf(`
+		<nes20db>
+			<game name="Test Game">
+				<rom size="%d" crc32="%08X" />
+				<pcb mapper="2" submapper="0" mirroring="H" battery="0" />
+			</game>
+		</nes20db>`, len(data)-headersSize, crc32.ChecksumIEEE(data[headersSize:]))))
+	require.NoError(t, err)
+	embedded := loadGameDatabase
+	loadGameDatabase = func() (gameDatabase, error) { return db, nil }
+	t.Cleanup(func() { loadGameDatabase = embedded })
+
+	cart, err := FromBytes(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, cart.headers.MapperId)
+	require.Equal(t, HorizontalMirroring, cart.headers.Mirroring)
+	game, ok := cart.Game()
+	require.True(t, ok)
+	require.Equal(t, "Test Game", game.Title)
+	require.Equal(t, "mapper 2", game.Board)
+
+	cart, err = FromBytes(data, WithoutGameDatabase())
+	require.NoError(t, err)
+	require.Equal(t, 0, cart.headers.MapperId)
+}
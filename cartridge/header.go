@@ -0,0 +1,207 @@
+package cartridge
+
+import (
+	"bytes"
+	"io"
+)
+
+const (
+	programBanksIndex       = 4
+	charactersBanksIndex    = 5
+	firstControlByteIndex   = 6
+	secondControlByteIndex  = 7
+	ramBanksQuantityIndex   = 8
+	mapperVariantIndex      = 8
+	romSizeMsbIndex         = 9
+	programRamShiftIndex    = 10
+	characterRamShiftIndex  = 11
+	timingIndex             = 12
+	systemTypeIndex         = 13
+	miscRomsIndex           = 14
+	expansionDeviceIndex    = 15
+	iNESPaddingIndex        = 12
+	headersSize             = 16
+	nes2Identifier          = 0b1000
+	archaicINESIdentifier   = 0b0100
+	formatIdentifierMask    = 0b1100
+	exponentMultiplierMSB   = 0x0F
+	ramShiftBaseSize        = 64
+	consoleTypeMask         = 0b00000011
+	timingMask              = 0b00000011
+	miscRomsMask            = 0b00000011
+	expansionDeviceMask     = 0b00111111
+	defaultCharacterRamSize = chrBankSize
+)
+
+var iNESMagic = []byte{'N', 'E', 'S', 0x1A}
+
+// ConsoleType is the kind of system a rom was made for.
+type ConsoleType uint8
+
+const (
+	ConsoleNES ConsoleType = iota
+	ConsoleVsSystem
+	ConsolePlaychoice10
+	ConsoleExtended
+)
+
+// TimingMode is the CPU/PPU timing a rom expects.
+type TimingMode uint8
+
+const (
+	TimingNTSC TimingMode = iota
+	TimingPAL
+	TimingMultipleRegion
+	TimingDendy
+)
+
+// Header holds the information in the 16 byte header of iNES and NES 2.0
+// files. Fields only NES 2.0 can express keep their zero value, or the
+// value the iNES conventions imply, for iNES files.
+type Header struct {
+	IsNES2                 bool
+	ProgramBanksQuantity   int
+	ProgramRomSize         int
+	CharacterBanksQuantity int
+	CharacterRomSize       int
+	Mirroring              MirroringType
+	AlternativeNametables  bool
+	// SolderedMirroring is the mirroring selected by bit 0 alone, which
+	// boards reusing bit 3 for their own nametable layouts still need.
+	SolderedMirroring   MirroringType
+	UseBatteryBackedRam bool
+	UseCharacterRam     bool
+	UseTrainer          bool
+	MapperId            int
+	Submapper           int
+	// ProgramRamSize and ProgramNvramSize are the volatile and the battery
+	// backed PRG-RAM sizes. iNES files only have the first one, and the
+	// battery flag tells whether it is backed.
+	ProgramRamSize     int
+	ProgramNvramSize   int
+	CharacterRamSize   int
+	CharacterNvramSize int
+	Timing             TimingMode
+	ConsoleType        ConsoleType
+	// VsPPUType and VsHardwareType describe the Vs. System arcade board,
+	// ExtendedConsoleType the console of ConsoleExtended roms.
+	VsPPUType           uint8
+	VsHardwareType      uint8
+	ExtendedConsoleType uint8
+	MiscRomCount        int
+	ExpansionDevice     uint8
+}
+
+func readHeaders(reader io.Reader) (*Header, error) {
+	headers := make([]byte, headersSize)
+	if err := readFull(reader, headers); err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(headers, iNESMagic) {
+		return nil, ErrInvalidRomFile
+	}
+	return parseHeaders(headers), nil
+}
+
+func parseHeaders(headers []byte) *Header {
+	firstControlByte := headers[firstControlByteIndex]
+	secondControlByte := headers[secondControlByteIndex]
+	format := secondControlByte & formatIdentifierMask
+	isNES2 := format == nes2Identifier
+	// dumps from old tools filled bytes 7-15 with their signature, so the
+	// upper mapper nibble can only be trusted when the padding is clean
+	isArchaicINES := format == archaicINESIdentifier ||
+		(!isNES2 && !bytes.Equal(headers[iNESPaddingIndex:], make([]byte, headersSize-iNESPaddingIndex)))
+
+	useVerticalMirroring := (firstControlByte & 0b1) == 1
+	alternativeNametables := (firstControlByte & 0b1000) != 0
+	solderedMirroring := HorizontalMirroring
+	if useVerticalMirroring {
+		solderedMirroring = VerticalMirroring
+	}
+	mirroring := solderedMirroring
+	if alternativeNametables {
+		mirroring = FourScreenMirroring
+	}
+
+	header := &Header{
+		IsNES2:                isNES2,
+		Mirroring:             mirroring,
+		AlternativeNametables: alternativeNametables,
+		SolderedMirroring:     solderedMirroring,
+		UseBatteryBackedRam:   (firstControlByte & 0b10) != 0,
+		UseTrainer:            (firstControlByte & 0b100) != 0,
+		MapperId:              int(firstControlByte >> 4),
+	}
+	if !isArchaicINES {
+		header.MapperId |= int(secondControlByte & 0b11110000)
+	}
+
+	if isNES2 {
+		parseNES2Headers(header, headers)
+	} else {
+		header.ProgramRomSize = int(headers[programBanksIndex]) * prgBankSize
+		header.CharacterRomSize = int(headers[charactersBanksIndex]) * chrBankSize
+		header.ProgramRamSize = programRamBankSize
+		if !isArchaicINES {
+			header.ProgramRamSize = max(int(headers[ramBanksQuantityIndex]), 1) * programRamBankSize
+			header.ConsoleType = ConsoleType(secondControlByte & consoleTypeMask)
+		}
+		if header.CharacterRomSize == 0 {
+			header.CharacterRamSize = defaultCharacterRamSize
+		}
+	}
+
+	header.ProgramBanksQuantity = max(header.ProgramRomSize/prgBankSize, 1)
+	header.CharacterBanksQuantity = header.CharacterRomSize / chrBankSize
+	if header.CharacterRomSize == 0 {
+		header.UseCharacterRam = true
+		header.CharacterBanksQuantity = 1
+		if header.CharacterRamSize+header.CharacterNvramSize == 0 {
+			header.CharacterRamSize = defaultCharacterRamSize
+		}
+	}
+	return header
+}
+
+func parseNES2Headers(header *Header, headers []byte) {
+	header.MapperId |= int(headers[mapperVariantIndex]&0b1111) << 8
+	header.Submapper = int(headers[mapperVariantIndex] >> 4)
+	header.ProgramRomSize = nes2RomSize(headers[programBanksIndex], headers[romSizeMsbIndex]&0b1111, prgBankSize)
+	header.CharacterRomSize = nes2RomSize(headers[charactersBanksIndex], headers[romSizeMsbIndex]>>4, chrBankSize)
+	header.ProgramRamSize = nes2RamSize(headers[programRamShiftIndex] & 0b1111)
+	header.ProgramNvramSize = nes2RamSize(headers[programRamShiftIndex] >> 4)
+	header.CharacterRamSize = nes2RamSize(headers[characterRamShiftIndex] & 0b1111)
+	header.CharacterNvramSize = nes2RamSize(headers[characterRamShiftIndex] >> 4)
+	header.Timing = TimingMode(headers[timingIndex] & timingMask)
+	header.ConsoleType = ConsoleType(headers[secondControlByteIndex] & consoleTypeMask)
+	switch header.ConsoleType {
+	case ConsoleVsSystem:
+		header.VsPPUType = headers[systemTypeIndex] & 0b1111
+		header.VsHardwareType = headers[systemTypeIndex] >> 4
+	case ConsoleExtended:
+		header.ExtendedConsoleType = headers[systemTypeIndex] & 0b1111
+	}
+	header.MiscRomCount = int(headers[miscRomsIndex] & miscRomsMask)
+	header.ExpansionDevice = headers[expansionDeviceIndex] & expansionDeviceMask
+}
+
+// nes2RomSize decodes a NES 2.0 rom size. When the most significant nibble
+// is $F, the least significant byte holds an exponent and a multiplier for
+// sizes that are not a multiple of the bank size.
+func nes2RomSize(lsb uint8, msb uint8, bankSize int) int {
+	if msb != exponentMultiplierMSB {
+		return (int(msb)<<8 | int(lsb)) * bankSize
+	}
+	exponent := lsb >> 2
+	multiplier := int(lsb&0b11)*2 + 1
+	return (1 << exponent) * multiplier
+}
+
+// nes2RamSize decodes a NES 2.0 shift count, where zero means no memory.
+func nes2RamSize(shift uint8) int {
+	if shift == 0 {
+		return 0
+	}
+	return ramShiftBaseSize << shift
+}
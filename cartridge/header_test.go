@@ -0,0 +1,130 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []byte
+		want    Header
+	}{
+		{
+			name:    "iNES with chr ram",
+			headers: []byte{'N', 'E', 'S', 0x1A, 2, 0, 0b00100011, 0b01000000, 0, 0, 0, 0, 0, 0, 0, 0},
+			want: Header{
+				ProgramBanksQuantity:   2,
+				ProgramRomSize:         2 * prgBankSize,
+				CharacterBanksQuantity: 1,
+				Mirroring:              VerticalMirroring,
+				SolderedMirroring:      VerticalMirroring,
+				UseBatteryBackedRam:    true,
+				UseCharacterRam:        true,
+				MapperId:               0x42,
+				ProgramRamSize:         programRamBankSize,
+				CharacterRamSize:       chrBankSize,
+			},
+		},
+		{
+			name:    "archaic iNES ignores the upper mapper nibble",
+			headers: []byte{'N', 'E', 'S', 0x1A, 1, 1, 0b00011000, 'D', 'i', 's', 'k', 'D', 'u', 'd', 'e', '!'},
+			want: Header{
+				ProgramBanksQuantity:   1,
+				ProgramRomSize:         prgBankSize,
+				CharacterBanksQuantity: 1,
+				CharacterRomSize:       chrBankSize,
+				Mirroring:              FourScreenMirroring,
+				AlternativeNametables:  true,
+				SolderedMirroring:      HorizontalMirroring,
+				MapperId:               1,
+				ProgramRamSize:         programRamBankSize,
+			},
+		},
+		{
+			name:    "NES 2.0",
+			headers: []byte{'N', 'E', 'S', 0x1A, 0x02, 0x01, 0b01000000, 0b00011001, 0x21, 0x10, 0x70, 0x07, 0x01, 0x23, 0x02, 0x01},
+			want: Header{
+				IsNES2:                 true,
+				ProgramBanksQuantity:   2,
+				ProgramRomSize:         2 * prgBankSize,
+				CharacterBanksQuantity: 0x101,
+				CharacterRomSize:       0x101 * chrBankSize,
+				Mirroring:              HorizontalMirroring,
+				SolderedMirroring:      HorizontalMirroring,
+				MapperId:               0x114,
+				Submapper:              2,
+				ProgramNvramSize:       8 * 1024,
+				CharacterRamSize:       8 * 1024,
+				Timing:                 TimingPAL,
+				ConsoleType:            ConsoleVsSystem,
+				VsPPUType:              3,
+				VsHardwareType:         2,
+				MiscRomCount:           2,
+				ExpansionDevice:        1,
+			},
+		},
+		{
+			name:    "NES 2.0 exponent multiplier rom size",
+			headers: []byte{'N', 'E', 'S', 0x1A, 0b00111001, 0, 0, 0b00001000, 0, 0x0F, 0, 0, 0, 0, 0, 0},
+			want: Header{
+				IsNES2:                 true,
+				ProgramBanksQuantity:   3,
+				ProgramRomSize:         3 * 16 * 1024,
+				CharacterBanksQuantity: 1,
+				Mirroring:              HorizontalMirroring,
+				SolderedMirroring:      HorizontalMirroring,
+				UseCharacterRam:        true,
+				CharacterRamSize:       chrBankSize,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, *parseHeaders(test.headers))
+		})
+	}
+}
+
+func TestRomWithoutProgram(t *testing.T) {
+	_, err := FromBytes([]byte{'N', 'E', 'S', 0x1A, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+	require.ErrorIs(t, err, ErrInvalidRomFile)
+}
+
+func TestRomSmallerThanBank(t *testing.T) {
+	// NES 2.0 exponent 2^13 * 1, an 8KB PRG-ROM
+	data := []byte{'N', 'E', 'S', 0x1A, 13 << 2, 0x00, 0x00, 0x08, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	program := make([]byte, prgBank8kSize)
+	program[0] = 0x42
+	cart, err := FromBytes(append(data, program...))
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x42), cart.ReadPrgRom(0x8000))
+	require.Equal(t, uint8(0x42), cart.ReadPrgRom(0xA000), "the chip is mirrored to fill the bank")
+	require.Equal(t, uint8(0x42), cart.ReadPrgRom(0xE000))
+}
+
+func TestOversizedRom(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "NES 2.0 exponent past the file",
+			data: []byte{'N', 'E', 'S', 0x1A, 0xFC, 0x00, 0x00, 0x08, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name: "chr rom past the file",
+			data: append([]byte{'N', 'E', 'S', 0x1A, 0x01, 0x01, 0x00, 0x00, 0, 0, 0, 0, 0, 0, 0, 0}, make([]byte, prgBankSize)...),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := FromBytes(test.data)
+			require.ErrorIs(t, err, ErrInvalidRomFile)
+		})
+	}
+}
@@ -2,11 +2,11 @@ package cartridge
 
 type nrom struct {
 	banksQuantity int
-	rom           *cartridgeRom
+	rom           *Rom
 	mirroring     MirroringType
 }
 
-func newINES0(rom *cartridgeRom, headers *cartridgeHeaders) mapper {
+func newINES0(rom *Rom, headers *Header) Mapper {
 	return &nrom{
 		banksQuantity: headers.ProgramBanksQuantity,
 		rom:           rom,
@@ -19,24 +19,32 @@ func (m *nrom) Mirroring() MirroringType {
 }
 
 func (m *nrom) ReadPrg(addr uint16) uint8 {
-	if addr < 0x8000 {
+	offset, ok := m.ProgramRomOffset(addr)
+	if !ok {
 		return 0
 	}
+	return m.rom.Program[offset]
+}
+
+func (m *nrom) ProgramRomOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
 	addr -= 0x8000
 	romSize := len(m.rom.Program)
 	if addr >= uint16(romSize) && m.banksQuantity == 1 {
 		addr -= prgBankSize
 	}
-	return m.rom.Program[addr]
+	return int(addr), true
 }
 
 func (m *nrom) WritePrg(_ uint16, _ uint8) {
 }
 
 func (m *nrom) ReadChr(addr uint16) uint8 {
-	return m.rom.Character.Read(addr)
+	return m.rom.Character.Read(int(addr))
 }
 
 func (m *nrom) WriteChr(addr uint16, data uint8) {
-	m.rom.Character.Write(addr, data)
+	m.rom.Character.Write(int(addr), data)
 }
@@ -0,0 +1,39 @@
+package cartridge
+
+const txsromNametableBit = 0b10000000
+
+// txsrom implements the TKSROM and TLSROM boards (mapper 118), an MMC3
+// with CIRAM A10 wired to bit 7 of the CHR bank used by each nametable
+// quadrant instead of to the mirroring register.
+type txsrom struct {
+	*mmc3
+}
+
+func newINES118(rom *Rom, headers *Header) Mapper {
+	mmc3 := newMMC3(rom, headers)
+	mmc3.hasMirroringControl = false
+	return &txsrom{mmc3: mmc3}
+}
+
+func (m *txsrom) NametablePage(addr uint16) int {
+	// nametable fetches keep PPU A12 low, so they go through the banks of
+	// the first pattern table window
+	bank := m.chrBank(addr & (mmc3ChrInversionAddr - 1))
+	if bank&txsromNametableBit != 0 {
+		return 1
+	}
+	return 0
+}
+
+func (m *txsrom) chrAddress(addr uint16) int {
+	bank := m.chrBank(addr) &^ txsromNametableBit
+	return bankAddress(m.rom.Character.Size(), bank, chrBank1kSize, addr)
+}
+
+func (m *txsrom) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *txsrom) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
@@ -0,0 +1,42 @@
+package cartridge
+
+const (
+	tqromChrRamBit  = 0b01000000
+	tqromChrRamSize = 8 * 1024
+	tqromBankMask   = 0b00111111
+)
+
+// tqrom implements the TQROM board (mapper 119), an MMC3 carrying 8KB of
+// CHR-RAM next to its CHR-ROM. Bit 6 of each CHR bank register picks the
+// ram instead of the rom.
+type tqrom struct {
+	*mmc3
+	chr *characterRomAndRam
+}
+
+func newINES119(rom *Rom, headers *Header) Mapper {
+	ramSize := headers.CharacterRamSize
+	if ramSize == 0 {
+		ramSize = tqromChrRamSize
+	}
+	return &tqrom{
+		mmc3: newMMC3(rom, headers),
+		chr:  newCharacterRomAndRam(rom.Character, ramSize),
+	}
+}
+
+func (m *tqrom) chrAddress(addr uint16) int {
+	bank := m.chrBank(addr)
+	if bank&tqromChrRamBit != 0 {
+		return m.chr.RamOffset() + bankAddress(len(m.chr.ram), bank, chrBank1kSize, addr)
+	}
+	return bankAddress(m.chr.RamOffset(), bank&tqromBankMask, chrBank1kSize, addr)
+}
+
+func (m *tqrom) ReadChr(addr uint16) uint8 {
+	return m.chr.Read(m.chrAddress(addr))
+}
+
+func (m *tqrom) WriteChr(addr uint16, data uint8) {
+	m.chr.Write(m.chrAddress(addr), data)
+}
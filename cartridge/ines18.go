@@ -0,0 +1,158 @@
+package cartridge
+
+const (
+	jalecoIrqEnableBit = 0b0001
+	jalecoIrq4BitBit   = 0b1000
+	jalecoIrq8BitBit   = 0b0100
+	jalecoIrq12BitBit  = 0b0010
+	jalecoNibbleMask   = 0b1111
+	jalecoRamEnableBit = 0b0001
+	jalecoRamWriteBit  = 0b0010
+)
+
+// jalecoSS88006 implements the Jaleco SS88006 board (mapper 18). Every
+// bank register is written 4 bits at a time through a pair of addresses,
+// and the IRQ counter can be narrowed to 4, 8, 12 or 16 bits.
+type jalecoSS88006 struct {
+	rom          *Rom
+	prgBanks     [3]uint8
+	chrBanks     [8]uint8
+	mirroring    MirroringType
+	ramControl   uint8
+	irqReload    uint16
+	irqCounter   uint16
+	irqWidthMask uint16
+	irqEnabled   bool
+	irq          bool
+}
+
+func newINES18(rom *Rom, headers *Header) Mapper {
+	return &jalecoSS88006{
+		rom:          rom,
+		mirroring:    headers.Mirroring,
+		irqWidthMask: 0xFFFF,
+	}
+}
+
+func (m *jalecoSS88006) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *jalecoSS88006) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	window := (addr - 0x8000) / prgBank8kSize
+	bank := prgBank8kCount(m.rom) - 1
+	if int(window) < len(m.prgBanks) {
+		bank = int(m.prgBanks[window])
+	}
+	return readPrg8k(m.rom, bank, addr)
+}
+
+func (m *jalecoSS88006) ProgramRamReadable() bool {
+	return m.ramControl&jalecoRamEnableBit != 0
+}
+
+func (m *jalecoSS88006) ProgramRamWritable() bool {
+	return m.ProgramRamReadable() && m.ramControl&jalecoRamWriteBit != 0
+}
+
+func (m *jalecoSS88006) WritePrg(addr uint16, data uint8) {
+	if addr < 0x8000 {
+		return
+	}
+	// registers are laid out four per 4KB page: $8000-$8003, $9000-$9003...
+	register := (int(addr-0x8000)>>12)*4 + int(addr&0b11)
+	nibble := data & jalecoNibbleMask
+	highNibble := register&1 != 0
+
+	switch {
+	case register < 4:
+		setNibble(&m.prgBanks[register>>1], nibble, highNibble)
+	case register < 6:
+		setNibble(&m.prgBanks[2], nibble, highNibble)
+	case register == 6:
+		m.ramControl = data
+	case register == 7:
+		// $9003 isn't connected on the board
+	case register >= 8 && register < 24:
+		setNibble(&m.chrBanks[(register-8)>>1], nibble, highNibble)
+	case register >= 24 && register < 28:
+		shift := uint16(register-24) * 4
+		m.irqReload = m.irqReload&^(jalecoNibbleMask<<shift) | uint16(nibble)<<shift
+	case register == 28:
+		m.irqCounter = m.irqReload
+		m.irq = false
+	case register == 29:
+		m.writeIrqControl(data)
+	case register == 30:
+		m.writeMirroring(data)
+	}
+}
+
+func (m *jalecoSS88006) writeIrqControl(data uint8) {
+	m.irq = false
+	m.irqEnabled = data&jalecoIrqEnableBit != 0
+	switch {
+	case data&jalecoIrq4BitBit != 0:
+		m.irqWidthMask = 0x000F
+	case data&jalecoIrq8BitBit != 0:
+		m.irqWidthMask = 0x00FF
+	case data&jalecoIrq12BitBit != 0:
+		m.irqWidthMask = 0x0FFF
+	default:
+		m.irqWidthMask = 0xFFFF
+	}
+}
+
+func (m *jalecoSS88006) writeMirroring(data uint8) {
+	switch data & 0b11 {
+	case 0:
+		m.mirroring = HorizontalMirroring
+	case 1:
+		m.mirroring = VerticalMirroring
+	case 2:
+		m.mirroring = SingleScreenLowerMirroring
+	case 3:
+		m.mirroring = SingleScreenUpperMirroring
+	}
+}
+
+func setNibble(register *uint8, nibble uint8, high bool) {
+	if high {
+		*register = *register&0x0F | nibble<<4
+	} else {
+		*register = *register&0xF0 | nibble
+	}
+}
+
+// ClockCPU decrements only the bits selected by the IRQ width, leaving the
+// upper ones untouched, and fires when the selected bits wrap around.
+func (m *jalecoSS88006) ClockCPU() {
+	if !m.irqEnabled {
+		return
+	}
+	counter := m.irqCounter & m.irqWidthMask
+	if counter == 0 {
+		m.irq = true
+	}
+	m.irqCounter = m.irqCounter&^m.irqWidthMask | (counter-1)&m.irqWidthMask
+}
+
+func (m *jalecoSS88006) IRQ() bool {
+	return m.irq
+}
+
+func (m *jalecoSS88006) chrAddress(addr uint16) int {
+	bank := int(m.chrBanks[addr/chrBank1kSize])
+	return bankAddress(m.rom.Character.Size(), bank, chrBank1kSize, addr)
+}
+
+func (m *jalecoSS88006) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *jalecoSS88006) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
@@ -0,0 +1,77 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJalecoIrqCounterWidth(t *testing.T) {
+	tests := []struct {
+		name        string
+		control     uint8
+		reload      uint16
+		wantCycles  int
+		wantCounter uint16
+	}{
+		{
+			name:        "16 bit counter",
+			control:     0b0001,
+			reload:      0x0102,
+			wantCycles:  0x0103,
+			wantCounter: 0xFFFF,
+		},
+		{
+			name:        "12 bit counter keeps the upper nibble",
+			control:     0b0011,
+			reload:      0xA002,
+			wantCycles:  3,
+			wantCounter: 0xAFFF,
+		},
+		{
+			name:        "8 bit counter keeps the upper byte",
+			control:     0b0101,
+			reload:      0x1204,
+			wantCycles:  5,
+			wantCounter: 0x12FF,
+		},
+		{
+			name:        "4 bit counter keeps the upper bits",
+			control:     0b1001,
+			reload:      0x1230,
+			wantCycles:  1,
+			wantCounter: 0x123F,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newINES18(&Rom{}, &Header{}).(*jalecoSS88006)
+			for i := range 4 {
+				m.WritePrg(0xE000+uint16(i), uint8(test.reload>>(4*i)))
+			}
+			m.WritePrg(0xF000, 0)
+			m.WritePrg(0xF001, test.control)
+
+			cycles := 0
+			for !m.IRQ() {
+				m.ClockCPU()
+				cycles++
+			}
+			require.Equal(t, test.wantCycles, cycles)
+			require.Equal(t, test.wantCounter, m.irqCounter)
+		})
+	}
+}
+
+func TestJalecoRegisterDecoding(t *testing.T) {
+	m := newINES18(&Rom{}, &Header{}).(*jalecoSS88006)
+	m.WritePrg(0x9002, 0b11)
+	m.WritePrg(0x9003, 0x0F)
+	require.Equal(t, uint8(0b11), m.ramControl)
+	require.Zero(t, m.irqReload, "$9003 doesn't reach the IRQ reload")
+
+	m.WritePrg(0xE002, 0x05)
+	m.WritePrg(0xE003, 0x0A)
+	require.Equal(t, uint16(0xA500), m.irqReload)
+}
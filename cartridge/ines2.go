@@ -1,18 +1,48 @@
 package cartridge
 
+const uxromAndBusConflictsSubmapper = 2
+
 type ines2 struct {
 	mirroring    MirroringType
 	selectedBank int
-	rom          *cartridgeRom
-	headers      *cartridgeHeaders
+	rom          *Rom
+	headers      *Header
+	busConflicts bool
+	// fixedFirstBank swaps the windows, mapping the first bank at $8000
+	// and the selected one at $C000 (mapper 180).
+	fixedFirstBank bool
+	// bankShift selects where the bank number starts in the written
+	// value (mapper 94 uses bits 2-4).
+	bankShift uint8
 }
 
-func newINES2(rom *cartridgeRom, headers *cartridgeHeaders) mapper {
+func newINES2(rom *Rom, headers *Header) Mapper {
 	return &ines2{
 		mirroring:    headers.Mirroring,
 		selectedBank: 0,
 		rom:          rom,
 		headers:      headers,
+		busConflicts: headers.Submapper == uxromAndBusConflictsSubmapper,
+	}
+}
+
+func newINES94(rom *Rom, headers *Header) Mapper {
+	return &ines2{
+		mirroring:    headers.Mirroring,
+		rom:          rom,
+		headers:      headers,
+		busConflicts: true,
+		bankShift:    2,
+	}
+}
+
+func newINES180(rom *Rom, headers *Header) Mapper {
+	return &ines2{
+		mirroring:      headers.Mirroring,
+		rom:            rom,
+		headers:        headers,
+		busConflicts:   true,
+		fixedFirstBank: true,
 	}
 }
 
@@ -20,27 +50,47 @@ func (m *ines2) Mirroring() MirroringType {
 	return m.mirroring
 }
 
-func (m *ines2) ReadPrg(addr16 uint16) uint8 {
+func (m *ines2) ReadPrg(addr uint16) uint8 {
+	offset, ok := m.ProgramRomOffset(addr)
+	if !ok {
+		return 0
+	}
+	return m.rom.Program[offset]
+}
+
+func (m *ines2) ProgramRomOffset(addr16 uint16) (int, bool) {
+	if addr16 < 0x8000 {
+		return 0, false
+	}
 	addr := int(addr16) - 0x8000
+	fixedBank := m.headers.ProgramBanksQuantity - 1
+	if m.fixedFirstBank {
+		fixedBank = 0
+	}
+	lowerBank, upperBank := m.selectedBank, fixedBank
+	if m.fixedFirstBank {
+		lowerBank, upperBank = fixedBank, m.selectedBank
+	}
 	if addr < 0x4000 {
-		addr += 16 * 1024 * m.selectedBank
-		return m.rom.Program[addr]
+		return addr + prgBankSize*lowerBank, true
 	}
-	addr += 16*1024*(m.headers.ProgramBanksQuantity-1) - 0x4000
-	return m.rom.Program[addr]
+	return addr + prgBankSize*upperBank - 0x4000, true
 }
 
 func (m *ines2) WritePrg(addr uint16, data uint8) {
 	if addr < 0x8000 {
 		return
 	}
-	m.selectedBank = int(data & 0b1111)
+	if m.busConflicts {
+		data &= m.ReadPrg(addr)
+	}
+	m.selectedBank = int(data>>m.bankShift) % m.headers.ProgramBanksQuantity
 }
 
 func (m *ines2) ReadChr(addr uint16) uint8 {
-	return m.rom.Character.Read(addr)
+	return m.rom.Character.Read(int(addr))
 }
 
 func (m *ines2) WriteChr(addr uint16, data uint8) {
-	m.rom.Character.Write(addr, data)
+	m.rom.Character.Write(int(addr), data)
 }
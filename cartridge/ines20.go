@@ -0,0 +1,431 @@
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/LucasWillBlumenau/nes/patch"
+)
+
+const (
+	fdsTimerRepeatBit    = 0b00000001
+	fdsTimerEnableBit    = 0b00000010
+	fdsDiskRegsEnableBit = 0b00000001
+	fdsSoundEnableBit    = 0b00000010
+	fdsMotorBit          = 0b00000001
+	fdsResetTransferBit  = 0b00000010
+	fdsReadModeBit       = 0b00000100
+	fdsMirroringBit      = 0b00001000
+	fdsCrcControlBit     = 0b00010000
+	fdsDiskReadyBit      = 0b01000000
+	fdsDiskIrqEnableBit  = 0b10000000
+)
+
+const (
+	fdsStatusTimerIrq     = 0b00000001
+	fdsStatusTransfer     = 0b00000010
+	fdsStatusEndOfHead    = 0b01000000
+	fdsDriveNoDisk        = 0b00000001
+	fdsDriveNotReady      = 0b00000010
+	fdsDriveWriteProtect  = 0b00000100
+	fdsDriveOpenBus       = 0b01000000
+	fdsExternalBatteryOk  = 0b10000000
+	fdsNoDisk             = -1
+	fdsEndOfHeadDelay     = 50000
+	fdsByteDelay          = 150
+	fdsDiskInsertDelay    = 1789773
+	fdsWriteLatency       = 2
+	fdsCrcPolynomial      = 0x8408
+	fdsRamStart           = 0x6000
+	fdsBiosStart          = 0xE000
+	fdsRegistersStart     = 0x4020
+	fdsAudioRegisterStart = 0x4040
+)
+
+// fdsRamAdapter implements the Famicom Disk System RAM adapter: 32KB of
+// PRG-RAM, 8KB of CHR-RAM, the bios, a CPU cycle timer IRQ and the disk
+// drive, which transfers a byte every 150 cycles while the motor spins.
+type fdsRamAdapter struct {
+	rom       *Rom
+	ram       []byte
+	sides     [][]byte
+	mirroring MirroringType
+	audio     *fdsAudio
+
+	diskRegsEnabled  bool
+	soundRegsEnabled bool
+	timerReload      uint16
+	timerCounter     uint16
+	timerRepeat      bool
+	timerEnabled     bool
+	timerIrq         bool
+	externalOutput   uint8
+
+	diskNumber     int
+	lastDiskNumber int
+	pendingDisk    int
+	insertDelay    int
+	motorOn        bool
+	resetTransfer  bool
+	readMode       bool
+	crcControl     bool
+	diskReady      bool
+	diskIrqEnabled bool
+	diskIrq        bool
+	transferred    bool
+	readData       uint8
+	writeData      uint8
+	position       int
+	delay          int
+	endOfHead      bool
+	scanning       bool
+	gapEnded       bool
+	previousCrc    bool
+	crc            uint16
+}
+
+func newINES20(rom *Rom, headers *Header) Mapper {
+	m := &fdsRamAdapter{
+		rom:         rom,
+		ram:         make([]byte, fdsRamSize),
+		mirroring:   headers.Mirroring,
+		audio:       newFDSAudio(),
+		pendingDisk: fdsNoDisk,
+		endOfHead:   true,
+	}
+	m.loadSides(rom.Disk)
+	return m
+}
+
+func (m *fdsRamAdapter) loadSides(disk []byte) {
+	m.sides = m.sides[:0]
+	for start := 0; start+fdsSideSize <= len(disk); start += fdsSideSize {
+		m.sides = append(m.sides, addFDSGaps(disk[start:start+fdsSideSize]))
+	}
+}
+
+func (m *fdsRamAdapter) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *fdsRamAdapter) ReadPrg(addr uint16) uint8 {
+	switch {
+	case addr >= fdsBiosStart:
+		return m.rom.Program[addr-fdsBiosStart]
+	case addr >= fdsRamStart:
+		return m.ram[addr-fdsRamStart]
+	case addr >= fdsAudioRegisterStart:
+		if m.soundRegsEnabled {
+			return m.audio.Read(addr)
+		}
+	case addr >= fdsRegistersStart && m.diskRegsEnabled:
+		return m.readRegister(addr)
+	}
+	return 0
+}
+
+func (m *fdsRamAdapter) readRegister(addr uint16) uint8 {
+	switch addr {
+	case 0x4030:
+		var status uint8
+		if m.timerIrq {
+			status |= fdsStatusTimerIrq
+		}
+		if m.transferred {
+			status |= fdsStatusTransfer
+		}
+		if m.endOfHead {
+			status |= fdsStatusEndOfHead
+		}
+		m.transferred = false
+		m.timerIrq = false
+		m.diskIrq = false
+		return status
+	case 0x4031:
+		m.transferred = false
+		m.diskIrq = false
+		return m.readData
+	case 0x4032:
+		status := uint8(fdsDriveOpenBus)
+		if !m.diskInserted() {
+			status |= fdsDriveNoDisk | fdsDriveWriteProtect
+		}
+		if !m.diskInserted() || !m.scanning {
+			status |= fdsDriveNotReady
+		}
+		return status
+	case 0x4033:
+		return fdsExternalBatteryOk
+	}
+	return 0
+}
+
+func (m *fdsRamAdapter) WritePrg(addr uint16, data uint8) {
+	switch {
+	case addr >= fdsBiosStart:
+	case addr >= fdsRamStart:
+		m.ram[addr-fdsRamStart] = data
+	case addr >= fdsAudioRegisterStart:
+		if m.soundRegsEnabled {
+			m.audio.Write(addr, data)
+		}
+	case addr >= fdsRegistersStart:
+		m.writeRegister(addr, data)
+	}
+}
+
+func (m *fdsRamAdapter) writeRegister(addr uint16, data uint8) {
+	if !m.diskRegsEnabled && addr >= 0x4024 && addr <= 0x4026 {
+		return
+	}
+	switch addr {
+	case 0x4020:
+		m.timerReload = m.timerReload&0xFF00 | uint16(data)
+	case 0x4021:
+		m.timerReload = m.timerReload&0x00FF | uint16(data)<<8
+	case 0x4022:
+		m.timerRepeat = data&fdsTimerRepeatBit != 0
+		m.timerEnabled = data&fdsTimerEnableBit != 0 && m.diskRegsEnabled
+		if m.timerEnabled {
+			m.timerCounter = m.timerReload
+		} else {
+			m.timerIrq = false
+		}
+	case 0x4023:
+		m.diskRegsEnabled = data&fdsDiskRegsEnableBit != 0
+		m.soundRegsEnabled = data&fdsSoundEnableBit != 0
+		if !m.diskRegsEnabled {
+			m.timerEnabled = false
+			m.timerIrq = false
+			m.diskIrq = false
+		}
+	case 0x4024:
+		m.writeData = data
+		m.transferred = false
+		m.diskIrq = false
+	case 0x4025:
+		m.motorOn = data&fdsMotorBit != 0
+		m.resetTransfer = data&fdsResetTransferBit != 0
+		m.readMode = data&fdsReadModeBit != 0
+		m.crcControl = data&fdsCrcControlBit != 0
+		m.diskReady = data&fdsDiskReadyBit != 0
+		m.diskIrqEnabled = data&fdsDiskIrqEnableBit != 0
+		m.mirroring = VerticalMirroring
+		if data&fdsMirroringBit != 0 {
+			m.mirroring = HorizontalMirroring
+		}
+		m.diskIrq = false
+	case 0x4026:
+		m.externalOutput = data
+	}
+}
+
+func (m *fdsRamAdapter) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(int(addr))
+}
+
+func (m *fdsRamAdapter) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(int(addr), data)
+}
+
+func (m *fdsRamAdapter) IRQ() bool {
+	return m.timerIrq || m.diskIrq
+}
+
+func (m *fdsRamAdapter) AudioSample() float32 {
+	return m.audio.Output()
+}
+
+func (m *fdsRamAdapter) ClockCPU() {
+	m.clockTimer()
+	m.clockDrive()
+	m.audio.Clock()
+}
+
+func (m *fdsRamAdapter) clockTimer() {
+	if !m.timerEnabled {
+		return
+	}
+	if m.timerCounter > 0 {
+		m.timerCounter--
+		return
+	}
+	m.timerIrq = true
+	m.timerCounter = m.timerReload
+	if !m.timerRepeat {
+		m.timerEnabled = false
+	}
+}
+
+func (m *fdsRamAdapter) diskInserted() bool {
+	return m.diskNumber != fdsNoDisk
+}
+
+// clockDrive advances the disk under the head. The drive reaches the end
+// of the disk and rewinds on its own, and the bios waits for the gap end
+// mark to know where the blocks start.
+func (m *fdsRamAdapter) clockDrive() {
+	if m.pendingDisk != fdsNoDisk {
+		if m.insertDelay > 0 {
+			m.insertDelay--
+		} else {
+			m.diskNumber = m.pendingDisk
+			m.pendingDisk = fdsNoDisk
+		}
+	}
+	if !m.diskInserted() || !m.motorOn {
+		m.endOfHead = true
+		m.scanning = false
+		return
+	}
+	if m.resetTransfer && !m.scanning {
+		return
+	}
+	if m.endOfHead {
+		m.delay = fdsEndOfHeadDelay
+		m.endOfHead = false
+		m.position = 0
+		m.gapEnded = false
+		return
+	}
+	if m.delay > 0 {
+		m.delay--
+		return
+	}
+
+	m.scanning = true
+	needIrq := m.diskIrqEnabled
+	side := m.sides[m.diskNumber]
+	if m.readMode {
+		data := side[m.position]
+		if !m.previousCrc {
+			m.updateCrc(data)
+		}
+		if !m.diskReady {
+			m.gapEnded = false
+			m.crc = 0
+		} else if data != 0 && !m.gapEnded {
+			// the gap end mark itself is not handed to the bios
+			m.gapEnded = true
+			needIrq = false
+		}
+		if m.gapEnded {
+			m.transferred = true
+			m.readData = data
+			if needIrq {
+				m.diskIrq = true
+			}
+		}
+	} else {
+		var data uint8
+		if !m.crcControl {
+			m.transferred = true
+			data = m.writeData
+			if needIrq {
+				m.diskIrq = true
+			}
+		}
+		if !m.diskReady {
+			data = 0
+		}
+		if !m.crcControl {
+			m.updateCrc(data)
+		} else {
+			if !m.previousCrc {
+				m.updateCrc(0)
+				m.updateCrc(0)
+			}
+			data = uint8(m.crc)
+			m.crc >>= 8
+		}
+		if m.position >= fdsWriteLatency {
+			side[m.position-fdsWriteLatency] = data
+		}
+		m.gapEnded = false
+	}
+
+	m.previousCrc = m.crcControl
+	m.position++
+	if m.position >= len(side) {
+		m.motorOn = false
+		m.endOfHead = true
+	} else {
+		m.delay = fdsByteDelay
+	}
+}
+
+func (m *fdsRamAdapter) updateCrc(data uint8) {
+	for bit := uint8(1); bit != 0; bit <<= 1 {
+		carry := m.crc&1 != 0
+		m.crc >>= 1
+		if carry {
+			m.crc ^= fdsCrcPolynomial
+		}
+		if data&bit != 0 {
+			m.crc ^= 0x8000
+		}
+	}
+}
+
+// InsertDisk puts the given side, counting from zero, in the drive after
+// taking out the current one. The drive takes a moment to see the new
+// disk, as the bios only notices a change after the disk was out a while.
+func (m *fdsRamAdapter) InsertDisk(side int) error {
+	if side < 0 || side >= len(m.sides) {
+		return fmt.Errorf("%w: side %d of %d", ErrInvalidDiskSide, side+1, len(m.sides))
+	}
+	if m.diskInserted() {
+		m.lastDiskNumber = m.diskNumber
+	}
+	m.diskNumber = fdsNoDisk
+	m.pendingDisk = side
+	m.insertDelay = fdsDiskInsertDelay
+	return nil
+}
+
+// EjectDisk takes the disk out of the drive, or puts the last one back in
+// when the drive is empty.
+func (m *fdsRamAdapter) EjectDisk() {
+	switch {
+	case m.diskInserted():
+		m.lastDiskNumber = m.diskNumber
+		m.diskNumber = fdsNoDisk
+	case m.pendingDisk == fdsNoDisk:
+		m.diskNumber = m.lastDiskNumber
+	}
+}
+
+// SwitchDiskSide flips to the next disk side, going back to the first one
+// after the last.
+func (m *fdsRamAdapter) SwitchDiskSide() error {
+	current := m.diskNumber
+	if current == fdsNoDisk {
+		current = m.lastDiskNumber
+	}
+	return m.InsertDisk((current + 1) % len(m.sides))
+}
+
+func (m *fdsRamAdapter) DiskSides() int {
+	return len(m.sides)
+}
+
+// SaveData returns the changes written to the disk as an IPS patch over
+// the original image, so the image file itself is never touched.
+func (m *fdsRamAdapter) SaveData() []byte {
+	disk := make([]byte, 0, len(m.rom.Disk))
+	for _, side := range m.sides {
+		disk = append(disk, removeFDSGaps(side)...)
+	}
+	return patch.CreateIPS(m.rom.Disk, disk)
+}
+
+func (m *fdsRamAdapter) LoadSaveData(data []byte) error {
+	disk, err := patch.Apply(m.rom.Disk, data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSaveData, err)
+	}
+	if len(disk) != len(m.rom.Disk) {
+		return fmt.Errorf("%w: disk save changes the disk size", ErrInvalidSaveData)
+	}
+	m.loadSides(disk)
+	return nil
+}
@@ -0,0 +1,78 @@
+package cartridge
+
+const (
+	ines225HighBankBit     = 0b0100000000000000
+	ines225MirroringBit    = 0b0010000000000000
+	ines225PrgModeBit      = 0b0001000000000000
+	ines225PrgBankMask     = 0b0000111111000000
+	ines225PrgBankShift    = 6
+	ines225ChrBankMask     = 0b0000000000111111
+	ines225HighBankShift   = 8
+	ines225NibbleRamStart  = 0x5800
+	ines225NibbleRamMask   = 0b11
+	ines225NibbleValueMask = 0b1111
+)
+
+// ines225 implements the 52/64/72-in-1 pirate multicart boards, which
+// latch the address of any write to $8000-$FFFF as the bank selection.
+type ines225 struct {
+	rom       *Rom
+	latch     uint16
+	nibbleRam [4]uint8
+}
+
+func newINES225(rom *Rom, _ *Header) Mapper {
+	return &ines225{rom: rom}
+}
+
+func (m *ines225) Reset() {
+	m.latch = 0
+}
+
+func (m *ines225) Mirroring() MirroringType {
+	if m.latch&ines225MirroringBit != 0 {
+		return HorizontalMirroring
+	}
+	return VerticalMirroring
+}
+
+func (m *ines225) highBank() int {
+	return int(m.latch&ines225HighBankBit) >> ines225HighBankShift
+}
+
+func (m *ines225) ReadPrg(addr uint16) uint8 {
+	if addr >= ines225NibbleRamStart && addr < 0x6000 {
+		return m.nibbleRam[addr&ines225NibbleRamMask]
+	}
+	if addr < 0x8000 {
+		return 0
+	}
+	bank := m.highBank() | int(m.latch&ines225PrgBankMask)>>ines225PrgBankShift
+	if m.latch&ines225PrgModeBit == 0 {
+		bank = bank&^1 | int(addr>>14)&1
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *ines225) WritePrg(addr uint16, data uint8) {
+	if addr >= ines225NibbleRamStart && addr < 0x6000 {
+		m.nibbleRam[addr&ines225NibbleRamMask] = data & ines225NibbleValueMask
+		return
+	}
+	if addr >= 0x8000 {
+		m.latch = addr
+	}
+}
+
+func (m *ines225) chrAddress(addr uint16) int {
+	bank := m.highBank() | int(m.latch&ines225ChrBankMask)
+	return bankAddress(m.rom.Character.Size(), bank, chrBankSize, addr)
+}
+
+func (m *ines225) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *ines225) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
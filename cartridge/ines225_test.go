@@ -0,0 +1,56 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestINES225Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		addr          uint16
+		wantPrg       []uint8
+		wantChr       uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "16KB mode",
+			addr:          0x8000 | ines225PrgModeBit | ines225MirroringBit | 5<<ines225PrgBankShift | 3,
+			wantPrg:       []uint8{10, 11, 10, 11},
+			wantChr:       3 * 8,
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "32KB mode ignores the low bank bit",
+			addr:          0x8000 | 5<<ines225PrgBankShift | 1,
+			wantPrg:       []uint8{8, 9, 10, 11},
+			wantChr:       1 * 8,
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "high bank",
+			addr:          0x8000 | ines225HighBankBit | ines225PrgModeBit | 2<<ines225PrgBankShift,
+			wantPrg:       []uint8{132, 133, 132, 133},
+			wantChr:       0,
+			wantMirroring: VerticalMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newINES225(newBankedTestRom(256, 64), &Header{})
+			m.WritePrg(test.addr, 0)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantChr, m.ReadChr(0))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
+
+func TestINES225NibbleRam(t *testing.T) {
+	m := newINES225(newBankedTestRom(4, 8), &Header{})
+	m.WritePrg(0x5801, 0xAB)
+	require.Equal(t, uint8(0x0B), m.ReadPrg(0x5801))
+	require.Equal(t, uint8(0x0B), m.ReadPrg(0x5FFD), "the ram is mirrored every 4 bytes")
+}
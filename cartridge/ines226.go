@@ -0,0 +1,58 @@
+package cartridge
+
+const (
+	ines226PrgBankMask     = 0b00011111
+	ines226PrgModeBit      = 0b00100000
+	ines226MirroringBit    = 0b01000000
+	ines226PrgBankHighBit  = 0b10000000
+	ines226PrgBankHighBits = 0b00000001
+)
+
+// ines226 implements the 76-in-1 and similar multicarts, with the PRG
+// bank split across two registers at $8000 and $8001.
+type ines226 struct {
+	rom       *Rom
+	registers [2]uint8
+}
+
+func newINES226(rom *Rom, _ *Header) Mapper {
+	return &ines226{rom: rom}
+}
+
+func (m *ines226) Reset() {
+	m.registers = [2]uint8{}
+}
+
+func (m *ines226) Mirroring() MirroringType {
+	if m.registers[0]&ines226MirroringBit != 0 {
+		return VerticalMirroring
+	}
+	return HorizontalMirroring
+}
+
+func (m *ines226) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	bank := int(m.registers[0]&ines226PrgBankMask) |
+		int(m.registers[0]&ines226PrgBankHighBit)>>2 |
+		int(m.registers[1]&ines226PrgBankHighBits)<<6
+	if m.registers[0]&ines226PrgModeBit == 0 {
+		bank = bank&^1 | int(addr>>14)&1
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *ines226) WritePrg(addr uint16, data uint8) {
+	if addr >= 0x8000 {
+		m.registers[addr&1] = data
+	}
+}
+
+func (m *ines226) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(int(addr))
+}
+
+func (m *ines226) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(int(addr), data)
+}
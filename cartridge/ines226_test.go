@@ -0,0 +1,44 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestINES226Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		writes        []mapperWrite
+		wantPrg       []uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "16KB mode",
+			writes:        []mapperWrite{{0x8000, ines226PrgModeBit | ines226MirroringBit | 3}},
+			wantPrg:       []uint8{6, 7, 6, 7},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "32KB mode with bit 5 of the bank in bit 7",
+			writes:        []mapperWrite{{0x8000, ines226PrgBankHighBit | 3}},
+			wantPrg:       []uint8{68, 69, 70, 71},
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "bit 6 of the bank in the second register",
+			writes:        []mapperWrite{{0x8000, ines226PrgModeBit | 2}, {0x8001, 1}},
+			wantPrg:       []uint8{132, 133, 132, 133},
+			wantMirroring: HorizontalMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newINES226(newBankedTestRom(256, 8), &Header{})
+			writeMapper(m, test.writes)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
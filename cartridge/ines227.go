@@ -0,0 +1,81 @@
+package cartridge
+
+const (
+	ines227PrgSizeBit     = 0b0000000000000001
+	ines227MirroringBit   = 0b0000000000000010
+	ines227PrgBankMask    = 0b0000000001111100
+	ines227PrgBankShift   = 2
+	ines227NromModeBit    = 0b0000000010000000
+	ines227OuterBankBit   = 0b0000000100000000
+	ines227OuterBankShift = 3
+	ines227LastBankBit    = 0b0000001000000000
+)
+
+// ines227 implements the 1200-in-1 style multicarts. The latched address
+// selects between an NROM-like mode and an UNROM-like mode where the upper
+// window is fixed to the first or last bank of the selected 128KB block.
+type ines227 struct {
+	rom   *Rom
+	latch uint16
+}
+
+func newINES227(rom *Rom, _ *Header) Mapper {
+	return &ines227{rom: rom}
+}
+
+func (m *ines227) Reset() {
+	m.latch = 0
+}
+
+func (m *ines227) Mirroring() MirroringType {
+	if m.latch&ines227MirroringBit != 0 {
+		return HorizontalMirroring
+	}
+	return VerticalMirroring
+}
+
+func (m *ines227) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	bank := int(m.latch&ines227PrgBankMask)>>ines227PrgBankShift |
+		int(m.latch&ines227OuterBankBit)>>ines227OuterBankShift
+	is32k := m.latch&ines227PrgSizeBit != 0
+	isUpperWindow := addr >= 0xC000
+
+	if m.latch&ines227NromModeBit != 0 {
+		if is32k {
+			bank = bank&^1 | int(addr>>14)&1
+		}
+	} else {
+		if is32k {
+			bank &^= 1
+		}
+		if isUpperWindow {
+			if m.latch&ines227LastBankBit != 0 {
+				bank |= 0b111
+			} else {
+				bank &= 0b111000
+			}
+		}
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *ines227) WritePrg(addr uint16, data uint8) {
+	if addr >= 0x8000 {
+		m.latch = addr
+	}
+}
+
+func (m *ines227) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(int(addr))
+}
+
+func (m *ines227) WriteChr(addr uint16, data uint8) {
+	// the board write-protects its CHR-RAM while in NROM mode
+	if m.latch&ines227NromModeBit != 0 {
+		return
+	}
+	m.rom.Character.Write(int(addr), data)
+}
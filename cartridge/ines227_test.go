@@ -0,0 +1,69 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestINES227Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		addr          uint16
+		wantPrg       []uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "NROM-128",
+			addr:          0x8000 | ines227NromModeBit | 5<<ines227PrgBankShift,
+			wantPrg:       []uint8{10, 11, 10, 11},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "NROM-256",
+			addr:          0x8000 | ines227NromModeBit | ines227PrgSizeBit | ines227MirroringBit | 5<<ines227PrgBankShift,
+			wantPrg:       []uint8{8, 9, 10, 11},
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "UNROM with the first bank fixed",
+			addr:          0x8000 | 5<<ines227PrgBankShift,
+			wantPrg:       []uint8{10, 11, 0, 1},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "UNROM with the last bank fixed",
+			addr:          0x8000 | ines227LastBankBit | 5<<ines227PrgBankShift,
+			wantPrg:       []uint8{10, 11, 14, 15},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "UNROM in the outer bank",
+			addr:          0x8000 | ines227OuterBankBit | ines227LastBankBit | 5<<ines227PrgBankShift,
+			wantPrg:       []uint8{74, 75, 78, 79},
+			wantMirroring: VerticalMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newINES227(newBankedTestRom(128, 0), &Header{})
+			m.WritePrg(test.addr, 0)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
+
+func TestINES227ChrRamProtection(t *testing.T) {
+	rom := newBankedTestRom(4, 0)
+	rom.Character = make(CharacterRam, chrBankSize)
+	m := newINES227(rom, &Header{UseCharacterRam: true})
+
+	m.WriteChr(0x0010, 0xAA)
+	require.Equal(t, uint8(0xAA), m.ReadChr(0x0010))
+
+	m.WritePrg(0x8000|ines227NromModeBit, 0)
+	m.WriteChr(0x0010, 0x55)
+	require.Equal(t, uint8(0xAA), m.ReadChr(0x0010), "CHR-RAM is read only in NROM mode")
+}
@@ -0,0 +1,88 @@
+package cartridge
+
+const (
+	ines228ChrBankHighMask  = 0b0000000000001111
+	ines228ChrBankLowMask   = 0b00000011
+	ines228PrgModeBit       = 0b0000000000100000
+	ines228PrgBankMask      = 0b0000011111000000
+	ines228PrgBankShift     = 6
+	ines228PrgChipMask      = 0b0001100000000000
+	ines228PrgChipShift     = 11
+	ines228MirroringBit     = 0b0010000000000000
+	ines228NibbleRamStart   = 0x4020
+	ines228NibbleRamMask    = 0b11
+	ines228NibbleValueMask  = 0b1111
+	ines228MissingChip      = 2
+	ines228LastChip         = 3
+	ines228BanksPerPrgChip  = 32
+	ines228ChrBankHighShift = 2
+)
+
+// ines228 implements the Active Enterprises boards (Action 52, Cheetahmen
+// II). Both the address and the data of a write carry bank bits.
+type ines228 struct {
+	rom       *Rom
+	latch     uint16
+	data      uint8
+	nibbleRam [4]uint8
+}
+
+func newINES228(rom *Rom, _ *Header) Mapper {
+	return &ines228{rom: rom}
+}
+
+func (m *ines228) Reset() {
+	m.latch = 0
+	m.data = 0
+}
+
+func (m *ines228) Mirroring() MirroringType {
+	if m.latch&ines228MirroringBit != 0 {
+		return HorizontalMirroring
+	}
+	return VerticalMirroring
+}
+
+func (m *ines228) ReadPrg(addr uint16) uint8 {
+	if addr >= ines228NibbleRamStart && addr < 0x6000 {
+		return m.nibbleRam[addr&ines228NibbleRamMask]
+	}
+	if addr < 0x8000 {
+		return 0
+	}
+	chip := int(m.latch&ines228PrgChipMask) >> ines228PrgChipShift
+	// Action 52 only populates three of the four 512KB chip sockets, the
+	// third one is empty and the dumps store the fourth chip in its place
+	if chip == ines228LastChip {
+		chip = ines228MissingChip
+	}
+	bank := chip*ines228BanksPerPrgChip + int(m.latch&ines228PrgBankMask)>>ines228PrgBankShift
+	if m.latch&ines228PrgModeBit == 0 {
+		bank = bank&^1 | int(addr>>14)&1
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *ines228) WritePrg(addr uint16, data uint8) {
+	if addr >= ines228NibbleRamStart && addr < 0x6000 {
+		m.nibbleRam[addr&ines228NibbleRamMask] = data & ines228NibbleValueMask
+		return
+	}
+	if addr >= 0x8000 {
+		m.latch = addr
+		m.data = data
+	}
+}
+
+func (m *ines228) chrAddress(addr uint16) int {
+	bank := int(m.latch&ines228ChrBankHighMask)<<ines228ChrBankHighShift | int(m.data&ines228ChrBankLowMask)
+	return bankAddress(m.rom.Character.Size(), bank, chrBankSize, addr)
+}
+
+func (m *ines228) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *ines228) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
@@ -0,0 +1,50 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestINES228Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		addr          uint16
+		data          uint8
+		wantPrg       []uint8
+		wantChr       uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "16KB mode",
+			addr:          0x8000 | 1<<ines228PrgChipShift | 3<<ines228PrgBankShift | ines228PrgModeBit | 2,
+			data:          1,
+			wantPrg:       []uint8{70, 71, 70, 71},
+			wantChr:       (2<<ines228ChrBankHighShift | 1) * 8,
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "32KB mode",
+			addr:          0x8000 | ines228MirroringBit | 3<<ines228PrgBankShift,
+			wantPrg:       []uint8{4, 5, 6, 7},
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "the last chip takes the place of the missing one",
+			addr:          0x8000 | ines228LastChip<<ines228PrgChipShift | ines228PrgModeBit,
+			wantPrg:       []uint8{128, 129, 128, 129},
+			wantMirroring: VerticalMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// three populated 512KB chips
+			m := newINES228(newBankedTestRom(192, 128), &Header{})
+			m.WritePrg(test.addr, test.data)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantChr, m.ReadChr(0))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cartridge
+
+const (
+	action53ChrBankRegister   = 0x00
+	action53InnerBankRegister = 0x01
+	action53ModeRegister      = 0x80
+	action53OuterBankRegister = 0x81
+	action53RegisterMask      = 0x81
+
+	action53ChrBankMask       = 0b00000011
+	action53InnerBankMask     = 0b00001111
+	action53MirroringBitShift = 4
+	action53ModeMask          = 0b00111111
+	action53MirroringMask     = 0b00000011
+	action53FixedHalfBit      = 0b00000100
+	action53UnromModeBit      = 0b00001000
+	action53GameSizeShift     = 4
+	action53ChrRamSize        = 32 * 1024
+)
+
+// action53 implements the mapper 28 board used by the Action 53 homebrew
+// compilations. It has no reset detection, so the registers survive a soft
+// reset and each game is expected to jump back to the menu on its own.
+type action53 struct {
+	rom       *Rom
+	chr       CharacterMemory
+	register  uint8
+	chrBank   uint8
+	innerBank uint8
+	mode      uint8
+	outerBank uint8
+}
+
+func newINES28(rom *Rom, headers *Header) Mapper {
+	chr := rom.Character
+	if headers.UseCharacterRam {
+		chr = make(CharacterRam, action53ChrRamSize)
+	}
+	// the outer bank powers up pointing to the last 32KB, where the menu is
+	return &action53{
+		rom:       rom,
+		chr:       chr,
+		outerBank: 0xFF,
+	}
+}
+
+func (m *action53) Mirroring() MirroringType {
+	switch m.mode & action53MirroringMask {
+	case 0:
+		return SingleScreenLowerMirroring
+	case 1:
+		return SingleScreenUpperMirroring
+	case 2:
+		return VerticalMirroring
+	default:
+		return HorizontalMirroring
+	}
+}
+
+func (m *action53) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	window := int(addr>>14) & 1
+	outerBank := int(m.outerBank) << 1
+	gameSize := m.mode >> action53GameSizeShift
+	innerMask := (2 << gameSize) - 1
+
+	var bank int
+	if m.mode&action53UnromModeBit == 0 {
+		innerBank := int(m.innerBank)<<1 | window
+		bank = outerBank&^innerMask | innerBank&innerMask
+	} else {
+		fixedWindow := 0
+		if m.mode&action53FixedHalfBit != 0 {
+			fixedWindow = 1
+		}
+		if window == fixedWindow {
+			bank = outerBank | fixedWindow
+		} else {
+			bank = outerBank&^innerMask | int(m.innerBank)&innerMask
+		}
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *action53) WritePrg(addr uint16, data uint8) {
+	if addr >= 0x5000 && addr < 0x6000 {
+		m.register = data & action53RegisterMask
+		return
+	}
+	if addr < 0x8000 {
+		return
+	}
+
+	switch m.register {
+	case action53ChrBankRegister:
+		m.chrBank = data & action53ChrBankMask
+		m.updateMirroringBit(data)
+	case action53InnerBankRegister:
+		m.innerBank = data & action53InnerBankMask
+		m.updateMirroringBit(data)
+	case action53ModeRegister:
+		m.mode = data & action53ModeMask
+	case action53OuterBankRegister:
+		m.outerBank = data
+	}
+}
+
+// updateMirroringBit handles the one-screen modes, where writes to the CHR
+// and inner bank registers also set bit 0 of the mode, which selects the
+// nametable displayed.
+func (m *action53) updateMirroringBit(data uint8) {
+	if m.mode&0b10 == 0 {
+		m.mode = m.mode&^1 | (data>>action53MirroringBitShift)&1
+	}
+}
+
+func (m *action53) chrAddress(addr uint16) int {
+	return bankAddress(m.chr.Size(), int(m.chrBank), chrBankSize, addr)
+}
+
+func (m *action53) ReadChr(addr uint16) uint8 {
+	return m.chr.Read(m.chrAddress(addr))
+}
+
+func (m *action53) WriteChr(addr uint16, data uint8) {
+	m.chr.Write(m.chrAddress(addr), data)
+}
@@ -0,0 +1,70 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAction53Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		writes        []mapperWrite
+		wantPrg       []uint8
+		wantChr       uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "power on maps the menu in the last 32KB",
+			wantPrg:       []uint8{60, 61, 62, 63},
+			wantMirroring: SingleScreenLowerMirroring,
+		},
+		{
+			name: "UNROM with the upper half fixed",
+			writes: []mapperWrite{
+				{0x5000, action53ModeRegister}, {0x8000, 1<<action53GameSizeShift | action53UnromModeBit | action53FixedHalfBit | 2},
+				{0x5000, action53OuterBankRegister}, {0x8000, 2},
+				{0x5000, action53InnerBankRegister}, {0x8000, 3},
+			},
+			wantPrg:       []uint8{14, 15, 10, 11},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name: "UNROM with the lower half fixed",
+			writes: []mapperWrite{
+				{0x5000, action53ModeRegister}, {0x8000, 1<<action53GameSizeShift | action53UnromModeBit | 3},
+				{0x5000, action53OuterBankRegister}, {0x8000, 2},
+				{0x5000, action53InnerBankRegister}, {0x8000, 3},
+			},
+			wantPrg:       []uint8{8, 9, 14, 15},
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "one screen mirroring from the mode register",
+			writes:        []mapperWrite{{0x5000, action53ModeRegister}, {0x8000, 1}},
+			wantPrg:       []uint8{60, 61, 62, 63},
+			wantMirroring: SingleScreenUpperMirroring,
+		},
+		{
+			name: "one screen mirroring follows the CHR register",
+			writes: []mapperWrite{
+				{0x5000, action53ModeRegister}, {0x8000, 0},
+				{0x5000, action53OuterBankRegister}, {0x8000, 1},
+				{0x5000, action53ChrBankRegister}, {0x8000, 1<<action53MirroringBitShift | 2},
+			},
+			wantPrg:       []uint8{4, 5, 6, 7},
+			wantChr:       2 * 8,
+			wantMirroring: SingleScreenUpperMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newINES28(newBankedTestRom(64, 32), &Header{})
+			writeMapper(m, test.writes)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantChr, m.ReadChr(0))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
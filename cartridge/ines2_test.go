@@ -0,0 +1,83 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUxromBankSwitching(t *testing.T) {
+	tests := []struct {
+		name      string
+		newMapper func(*Rom, *Header) Mapper
+		submapper int
+		addr      uint16
+		// bus is the rom byte at addr, driven together with the written value
+		bus     uint8
+		data    uint8
+		wantPrg []uint8
+	}{
+		{
+			name:      "mapper 2 without bus conflicts",
+			newMapper: newINES2,
+			addr:      0xC001,
+			data:      3,
+			wantPrg:   []uint8{6, 7, 14, 15},
+		},
+		{
+			name:      "mapper 2 with bus conflicts",
+			newMapper: newINES2,
+			submapper: uxromAndBusConflictsSubmapper,
+			addr:      0xC001,
+			bus:       0b001,
+			data:      0b011,
+			wantPrg:   []uint8{2, 3, 14, 15},
+		},
+		{
+			name:      "mapper 94 reads the bank from bits 2-4",
+			newMapper: newINES94,
+			addr:      0xC001,
+			bus:       0xFF,
+			data:      3 << 2,
+			wantPrg:   []uint8{6, 7, 14, 15},
+		},
+		{
+			name:      "mapper 94 ANDs the bank with the rom",
+			newMapper: newINES94,
+			addr:      0xC001,
+			bus:       0b01 << 2,
+			data:      0b11 << 2,
+			wantPrg:   []uint8{2, 3, 14, 15},
+		},
+		{
+			name:      "mapper 180 fixes the first bank at $8000",
+			newMapper: newINES180,
+			addr:      0x8001,
+			bus:       0xFF,
+			data:      3,
+			wantPrg:   []uint8{0, 1, 6, 7},
+		},
+		{
+			name:      "mapper 180 ANDs the bank with the rom",
+			newMapper: newINES180,
+			addr:      0x8001,
+			bus:       0b110,
+			data:      0b011,
+			wantPrg:   []uint8{0, 1, 4, 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rom := newBankedTestRom(16, 8)
+			headers := &Header{ProgramBanksQuantity: 8, Submapper: test.submapper}
+			m := test.newMapper(rom, headers)
+			offset, ok := m.(ProgramRomMapper).ProgramRomOffset(test.addr)
+			require.True(t, ok)
+			rom.Program[offset] = test.bus
+
+			m.WritePrg(test.addr, test.data)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package cartridge
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	unrom512PrgBankMask   = 0b00011111
+	unrom512ChrBankMask   = 0b01100000
+	unrom512ChrBankShift  = 5
+	unrom512ScreenMask    = 0b10000000
+	unrom512ChrRamSize    = 32 * 1024
+	unrom512FlashBankSize = 16 * 1024
+)
+
+type unrom512 struct {
+	ines2
+	flash     *sst39sf040
+	chrRam    CharacterRam
+	chrBank   int
+	flashable bool
+	oneScreen bool
+	// fourScreen boards take their nametables from the last CHR-RAM bank.
+	fourScreen bool
+}
+
+func newINES30(rom *Rom, headers *Header) Mapper {
+	chrRam, ok := rom.Character.(CharacterRam)
+	if !ok || len(chrRam) < unrom512ChrRamSize {
+		chrRam = make(CharacterRam, unrom512ChrRamSize)
+	}
+	mirroring := headers.Mirroring
+	oneScreen := headers.AlternativeNametables && headers.SolderedMirroring == HorizontalMirroring
+	if oneScreen {
+		mirroring = SingleScreenLowerMirroring
+	}
+	fourScreen := headers.AlternativeNametables && !oneScreen
+	return &unrom512{
+		ines2: ines2{
+			mirroring: mirroring,
+			rom:       rom,
+			headers:   headers,
+		},
+		flash:      newSST39SF040(rom.Program),
+		chrRam:     chrRam,
+		flashable:  headers.UseBatteryBackedRam,
+		oneScreen:  oneScreen,
+		fourScreen: fourScreen,
+	}
+}
+
+func (m *unrom512) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	if m.flash.softwareId {
+		return m.flash.Read(int(addr))
+	}
+	return m.ines2.ReadPrg(addr)
+}
+
+func (m *unrom512) WritePrg(addr uint16, data uint8) {
+	if addr < 0x8000 {
+		return
+	}
+	if m.flashable && addr < 0xC000 {
+		flashAddr := m.selectedBank*unrom512FlashBankSize + int(addr&0x3FFF)
+		m.flash.Write(flashAddr, data)
+		return
+	}
+	if !m.flashable {
+		data &= m.ReadPrg(addr)
+	}
+	m.selectedBank = int(data&unrom512PrgBankMask) % m.headers.ProgramBanksQuantity
+	m.chrBank = int(data&unrom512ChrBankMask) >> unrom512ChrBankShift
+	if m.oneScreen {
+		m.mirroring = SingleScreenLowerMirroring
+		if data&unrom512ScreenMask != 0 {
+			m.mirroring = SingleScreenUpperMirroring
+		}
+	}
+}
+
+func (m *unrom512) ReadChr(addr uint16) uint8 {
+	return m.chrRam.Read(m.chrBank*chrBankSize + int(addr))
+}
+
+func (m *unrom512) WriteChr(addr uint16, data uint8) {
+	m.chrRam.Write(m.chrBank*chrBankSize+int(addr), data)
+}
+
+func (m *unrom512) nametableAddress(addr uint16) int {
+	return len(m.chrRam) - chrBankSize + int(addr)%(4*nametablePageSize)
+}
+
+func (m *unrom512) ReadNametable(addr uint16) (uint8, bool) {
+	if !m.fourScreen {
+		return 0, false
+	}
+	return m.chrRam.Read(m.nametableAddress(addr)), true
+}
+
+func (m *unrom512) WriteNametable(addr uint16, data uint8) bool {
+	if !m.fourScreen {
+		return false
+	}
+	m.chrRam.Write(m.nametableAddress(addr), data)
+	return true
+}
+
+// SaveData returns the whole flash once the game programs it. Until then
+// the flash still matches the rom image and there is nothing to save.
+func (m *unrom512) SaveData() []byte {
+	if !m.flashable || !m.flash.modified {
+		return nil
+	}
+	return m.flash.data
+}
+
+func (m *unrom512) LoadSaveData(data []byte) error {
+	if len(data) != len(m.flash.data) {
+		return fmt.Errorf("%w: flash save has %d bytes, expected %d", ErrInvalidSaveData, len(data), len(m.flash.data))
+	}
+	// a save that differs from the rom has to be written back next time
+	if !bytes.Equal(m.flash.data, data) {
+		m.flash.modified = true
+	}
+	copy(m.flash.data, data)
+	return nil
+}
@@ -0,0 +1,94 @@
+package cartridge
+
+const (
+	iremG101MajorLeagueSubmapper = 1
+	iremG101PrgBankMask          = 0b00011111
+	iremG101MirroringBit         = 0b00000001
+	iremG101PrgModeBit           = 0b00000010
+)
+
+// iremG101 implements the Irem G-101 board (mapper 32).
+type iremG101 struct {
+	rom       *Rom
+	prgBanks  [2]int
+	chrBanks  [8]int
+	prgMode   uint8
+	mirroring MirroringType
+	// fixedMirroring is set for Major League, which wires the board to a
+	// single nametable and ignores the mirroring/mode register
+	fixedMirroring bool
+}
+
+func newINES32(rom *Rom, headers *Header) Mapper {
+	m := &iremG101{
+		rom:       rom,
+		mirroring: headers.Mirroring,
+	}
+	if headers.Submapper == iremG101MajorLeagueSubmapper {
+		m.mirroring = SingleScreenLowerMirroring
+		m.fixedMirroring = true
+	}
+	return m
+}
+
+func (m *iremG101) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *iremG101) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	lastBank := prgBank8kCount(m.rom) - 1
+	var bank int
+	switch (addr - 0x8000) / prgBank8kSize {
+	case 0:
+		bank = m.prgBanks[0]
+		if m.prgMode != 0 {
+			bank = lastBank - 1
+		}
+	case 1:
+		bank = m.prgBanks[1]
+	case 2:
+		bank = lastBank - 1
+		if m.prgMode != 0 {
+			bank = m.prgBanks[0]
+		}
+	default:
+		bank = lastBank
+	}
+	return readPrg8k(m.rom, bank, addr)
+}
+
+func (m *iremG101) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xF000 {
+	case 0x8000:
+		m.prgBanks[0] = int(data & iremG101PrgBankMask)
+	case 0x9000:
+		if m.fixedMirroring {
+			return
+		}
+		m.mirroring = VerticalMirroring
+		if data&iremG101MirroringBit != 0 {
+			m.mirroring = HorizontalMirroring
+		}
+		m.prgMode = data & iremG101PrgModeBit
+	case 0xA000:
+		m.prgBanks[1] = int(data & iremG101PrgBankMask)
+	case 0xB000:
+		m.chrBanks[addr&0b111] = int(data)
+	}
+}
+
+func (m *iremG101) chrAddress(addr uint16) int {
+	bank := m.chrBanks[addr/chrBank1kSize]
+	return bankAddress(m.rom.Character.Size(), bank, chrBank1kSize, addr)
+}
+
+func (m *iremG101) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *iremG101) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
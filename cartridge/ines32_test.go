@@ -0,0 +1,62 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIremG101Banks(t *testing.T) {
+	tests := []struct {
+		name          string
+		submapper     int
+		writes        []mapperWrite
+		wantPrg       []uint8
+		wantChr       []uint8
+		wantMirroring MirroringType
+	}{
+		{
+			name:          "power on",
+			wantPrg:       []uint8{0, 0, 14, 15},
+			wantChr:       []uint8{0, 0, 0, 0, 0, 0, 0, 0},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name: "switchable banks",
+			writes: []mapperWrite{
+				{0x8000, 3}, {0xA000, 5}, {0x9000, 0b01},
+				{0xB000, 8}, {0xB001, 9}, {0xB002, 10}, {0xB003, 11},
+				{0xB004, 12}, {0xB005, 13}, {0xB006, 14}, {0xB007, 15},
+			},
+			wantPrg:       []uint8{3, 5, 14, 15},
+			wantChr:       []uint8{8, 9, 10, 11, 12, 13, 14, 15},
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:          "swapped prg mode",
+			writes:        []mapperWrite{{0x8000, 3}, {0xA000, 5}, {0x9000, 0b10}},
+			wantPrg:       []uint8{14, 5, 3, 15},
+			wantChr:       []uint8{0, 0, 0, 0, 0, 0, 0, 0},
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name:          "Major League ignores the mode register",
+			submapper:     iremG101MajorLeagueSubmapper,
+			writes:        []mapperWrite{{0x8000, 3}, {0x9000, 0b11}},
+			wantPrg:       []uint8{3, 0, 14, 15},
+			wantChr:       []uint8{0, 0, 0, 0, 0, 0, 0, 0},
+			wantMirroring: SingleScreenLowerMirroring,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			headers := &Header{Mirroring: VerticalMirroring, Submapper: test.submapper}
+			m := newINES32(newBankedTestRom(16, 16), headers)
+			writeMapper(m, test.writes)
+			require.Equal(t, test.wantPrg, prgBanks(m))
+			require.Equal(t, test.wantChr, chrBanks(m))
+			require.Equal(t, test.wantMirroring, m.Mirroring())
+		})
+	}
+}
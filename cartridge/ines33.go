@@ -0,0 +1,92 @@
+package cartridge
+
+const (
+	taitoPrgBankMask     = 0b00111111
+	taitoTC0190Mirroring = 0b01000000
+)
+
+// taitoTC0190 implements the Taito TC0190 board (mapper 33): two
+// switchable 8KB PRG banks, two 2KB and four 1KB CHR banks.
+type taitoTC0190 struct {
+	rom        *Rom
+	prgBanks   [2]int
+	chr2kBanks [2]int
+	chr1kBanks [4]int
+	mirroring  MirroringType
+	// hasMirroringControl is false on the TC0690, which moved the
+	// mirroring bit from $8000 to $E000
+	hasMirroringControl bool
+}
+
+func newINES33(rom *Rom, headers *Header) Mapper {
+	return newTaitoTC0190(rom, headers)
+}
+
+func newTaitoTC0190(rom *Rom, headers *Header) *taitoTC0190 {
+	return &taitoTC0190{
+		rom:                 rom,
+		mirroring:           headers.Mirroring,
+		hasMirroringControl: true,
+	}
+}
+
+func (m *taitoTC0190) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *taitoTC0190) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	lastBank := prgBank8kCount(m.rom) - 1
+	var bank int
+	switch (addr - 0x8000) / prgBank8kSize {
+	case 0:
+		bank = m.prgBanks[0]
+	case 1:
+		bank = m.prgBanks[1]
+	case 2:
+		bank = lastBank - 1
+	default:
+		bank = lastBank
+	}
+	return readPrg8k(m.rom, bank, addr)
+}
+
+func (m *taitoTC0190) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xE003 {
+	case 0x8000:
+		m.prgBanks[0] = int(data & taitoPrgBankMask)
+		if m.hasMirroringControl {
+			m.mirroring = VerticalMirroring
+			if data&taitoTC0190Mirroring != 0 {
+				m.mirroring = HorizontalMirroring
+			}
+		}
+	case 0x8001:
+		m.prgBanks[1] = int(data & taitoPrgBankMask)
+	case 0x8002:
+		m.chr2kBanks[0] = int(data)
+	case 0x8003:
+		m.chr2kBanks[1] = int(data)
+	case 0xA000, 0xA001, 0xA002, 0xA003:
+		m.chr1kBanks[addr&0b11] = int(data)
+	}
+}
+
+func (m *taitoTC0190) chrAddress(addr uint16) int {
+	size := m.rom.Character.Size()
+	if addr < 0x1000 {
+		return bankAddress(size, m.chr2kBanks[addr/chrBank2kSize], chrBank2kSize, addr)
+	}
+	bank := m.chr1kBanks[(addr-0x1000)/chrBank1kSize]
+	return bankAddress(size, bank, chrBank1kSize, addr)
+}
+
+func (m *taitoTC0190) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *taitoTC0190) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
@@ -0,0 +1,23 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaitoTC0190Banks(t *testing.T) {
+	m := newINES33(newBankedTestRom(16, 16), &Header{Mirroring: VerticalMirroring})
+	writeMapper(m, []mapperWrite{
+		{0x8000, taitoTC0190Mirroring | 2}, {0x8001, 4},
+		{0x8002, 1}, {0x8003, 3},
+		{0xA000, 8}, {0xA001, 9}, {0xA002, 10}, {0xA003, 11},
+	})
+
+	require.Equal(t, []uint8{2, 4, 14, 15}, prgBanks(m))
+	require.Equal(t, []uint8{2, 3, 6, 7, 8, 9, 10, 11}, chrBanks(m), "the 2KB banks are numbered in 2KB units")
+	require.Equal(t, HorizontalMirroring, m.Mirroring())
+
+	m.WritePrg(0x8000, 2)
+	require.Equal(t, VerticalMirroring, m.Mirroring())
+}
@@ -0,0 +1,165 @@
+package cartridge
+
+const (
+	mmc3BankRegisterMask = 0b00000111
+	mmc3PrgModeBit       = 0b01000000
+	mmc3ChrInversionBit  = 0b10000000
+	mmc3PrgBankMask      = 0b00111111
+	mmc3MirroringBit     = 0b00000001
+	mmc3ChrInversionAddr = 0x1000
+	mmc3RamEnableBit     = 0b10000000
+	mmc3RamProtectBit    = 0b01000000
+)
+
+// mmc3 implements the Nintendo MMC3 (mapper 4): four 8KB PRG windows, two
+// of them switchable, two 2KB and four 1KB CHR banks that can swap pattern
+// tables, and a scanline IRQ counter clocked by PPU A12.
+type mmc3 struct {
+	rom          *Rom
+	registers    [8]uint8
+	bankSelect   uint8
+	prgMode      bool
+	chrInversion bool
+	mirroring    MirroringType
+	ramControl   uint8
+	irqLatch     uint8
+	irqCounter   uint8
+	irqReload    bool
+	irqEnabled   bool
+	irq          bool
+	// hasMirroringControl is false on TxSROM, which wires CIRAM A10 to
+	// the CHR banks instead of the mirroring register
+	hasMirroringControl bool
+}
+
+func newINES4(rom *Rom, headers *Header) Mapper {
+	return newMMC3(rom, headers)
+}
+
+func newMMC3(rom *Rom, headers *Header) *mmc3 {
+	return &mmc3{
+		rom:                 rom,
+		mirroring:           headers.Mirroring,
+		ramControl:          mmc3RamEnableBit,
+		hasMirroringControl: true,
+	}
+}
+
+func (m *mmc3) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *mmc3) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	return readPrg8k(m.rom, m.prgBank(addr), addr)
+}
+
+func (m *mmc3) ProgramRomOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	return bankAddress(len(m.rom.Program), m.prgBank(addr), prgBank8kSize, addr), true
+}
+
+func (m *mmc3) prgBank(addr uint16) int {
+	lastBank := prgBank8kCount(m.rom) - 1
+	switchable := int(m.registers[6] & mmc3PrgBankMask)
+	switch (addr - 0x8000) / prgBank8kSize {
+	case 0:
+		if m.prgMode {
+			return lastBank - 1
+		}
+		return switchable
+	case 1:
+		return int(m.registers[7] & mmc3PrgBankMask)
+	case 2:
+		if m.prgMode {
+			return switchable
+		}
+		return lastBank - 1
+	default:
+		return lastBank
+	}
+}
+
+func (m *mmc3) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xE001 {
+	case 0x8000:
+		m.bankSelect = data & mmc3BankRegisterMask
+		m.prgMode = data&mmc3PrgModeBit != 0
+		m.chrInversion = data&mmc3ChrInversionBit != 0
+	case 0x8001:
+		m.registers[m.bankSelect] = data
+	case 0xA000:
+		if m.hasMirroringControl {
+			m.mirroring = VerticalMirroring
+			if data&mmc3MirroringBit != 0 {
+				m.mirroring = HorizontalMirroring
+			}
+		}
+	case 0xA001:
+		m.ramControl = data
+	case 0xC000:
+		m.irqLatch = data
+	case 0xC001:
+		m.irqReload = true
+		m.irqCounter = 0
+	case 0xE000:
+		m.irqEnabled = false
+		m.irq = false
+	case 0xE001:
+		m.irqEnabled = true
+	}
+}
+
+func (m *mmc3) ProgramRamReadable() bool {
+	return m.ramControl&mmc3RamEnableBit != 0
+}
+
+func (m *mmc3) ProgramRamWritable() bool {
+	return m.ProgramRamReadable() && m.ramControl&mmc3RamProtectBit == 0
+}
+
+// chrBank returns the 1KB CHR bank number selected for addr, as the value
+// of the bank register. Boards may give the upper bits other meanings.
+func (m *mmc3) chrBank(addr uint16) int {
+	if m.chrInversion {
+		addr ^= mmc3ChrInversionAddr
+	}
+	slot := addr / chrBank1kSize
+	if slot < 4 {
+		// the 2KB banks ignore the lowest bit of their register
+		return int(m.registers[slot/2]&^0b1) + int(slot%2)
+	}
+	return int(m.registers[slot-2])
+}
+
+func (m *mmc3) chrAddress(addr uint16) int {
+	return bankAddress(m.rom.Character.Size(), m.chrBank(addr), chrBank1kSize, addr)
+}
+
+func (m *mmc3) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *mmc3) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
+
+func (m *mmc3) ClockScanline() {
+	if m.irqCounter == 0 || m.irqReload {
+		m.irqCounter = m.irqLatch
+		m.irqReload = false
+	} else {
+		m.irqCounter--
+	}
+	if m.irqCounter == 0 && m.irqEnabled {
+		m.irq = true
+	}
+}
+
+func (m *mmc3) IRQ() bool {
+	return m.irq
+}
@@ -0,0 +1,61 @@
+package cartridge
+
+const taitoTC0690Mirroring = 0b01000000
+
+// taitoTC0690 implements the Taito TC0690 board (mapper 48). It extends
+// the TC0190 banking with a scanline IRQ counter that behaves like the
+// MMC3 one.
+type taitoTC0690 struct {
+	*taitoTC0190
+	irqLatch   uint8
+	irqCounter uint8
+	irqReload  bool
+	irqEnabled bool
+	irq        bool
+}
+
+func newINES48(rom *Rom, headers *Header) Mapper {
+	tc0190 := newTaitoTC0190(rom, headers)
+	tc0190.hasMirroringControl = false
+	return &taitoTC0690{taitoTC0190: tc0190}
+}
+
+func (m *taitoTC0690) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xE003 {
+	case 0xC000:
+		// the latch is written inverted, so the value is the number of
+		// scanlines to wait subtracted from 256
+		m.irqLatch = data ^ 0xFF
+	case 0xC001:
+		m.irqReload = true
+		m.irqCounter = 0
+	case 0xC002:
+		m.irqEnabled = true
+	case 0xC003:
+		m.irqEnabled = false
+		m.irq = false
+	case 0xE000:
+		m.mirroring = VerticalMirroring
+		if data&taitoTC0690Mirroring != 0 {
+			m.mirroring = HorizontalMirroring
+		}
+	default:
+		m.taitoTC0190.WritePrg(addr, data)
+	}
+}
+
+func (m *taitoTC0690) ClockScanline() {
+	if m.irqCounter == 0 || m.irqReload {
+		m.irqCounter = m.irqLatch
+		m.irqReload = false
+	} else {
+		m.irqCounter--
+	}
+	if m.irqCounter == 0 && m.irqEnabled {
+		m.irq = true
+	}
+}
+
+func (m *taitoTC0690) IRQ() bool {
+	return m.irq
+}
@@ -0,0 +1,37 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaitoTC0690Mirroring(t *testing.T) {
+	m := newINES48(newBankedTestRom(16, 16), &Header{Mirroring: VerticalMirroring})
+	m.WritePrg(0x8000, taitoTC0190Mirroring|2)
+	require.Equal(t, VerticalMirroring, m.Mirroring(), "$8000 only selects the bank")
+	require.Equal(t, []uint8{2, 0, 14, 15}, prgBanks(m))
+
+	m.WritePrg(0xE000, taitoTC0690Mirroring)
+	require.Equal(t, HorizontalMirroring, m.Mirroring())
+}
+
+func TestTaitoTC0690Irq(t *testing.T) {
+	m := newINES48(newBankedTestRom(16, 16), &Header{}).(*taitoTC0690)
+	// the latch is written inverted
+	m.WritePrg(0xC000, 2^0xFF)
+	m.WritePrg(0xC001, 0)
+	m.WritePrg(0xC002, 0)
+
+	var irqs []bool
+	for range 4 {
+		m.ClockScanline()
+		irqs = append(irqs, m.IRQ())
+	}
+	require.Equal(t, []bool{false, false, true, true}, irqs)
+
+	m.WritePrg(0xC003, 0)
+	require.False(t, m.IRQ())
+	m.ClockScanline()
+	require.False(t, m.IRQ(), "the IRQ stays disabled")
+}
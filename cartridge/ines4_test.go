@@ -0,0 +1,83 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newMMC3TestRom(chrBanks int) *Rom {
+	chr := make(CharacterRom, chrBanks*chrBank1kSize)
+	for bank := range chrBanks {
+		chr[bank*chrBank1kSize] = uint8(bank)
+	}
+	return &Rom{
+		Program:   make([]byte, 4*prgBank8kSize),
+		Character: chr,
+	}
+}
+
+func TestTxsromNametablePage(t *testing.T) {
+	m := newINES118(newMMC3TestRom(128), &Header{}).(*txsrom)
+	m.WritePrg(0x8000, 0)
+	m.WritePrg(0x8001, 0x80)
+	m.WritePrg(0x8000, 1)
+	m.WritePrg(0x8001, 0x02)
+	m.WritePrg(0xA000, 1)
+
+	require.Equal(t, []int{1, 1, 0, 0}, nametablePages(m))
+	require.Equal(t, uint8(0), m.ReadChr(0x0000), "bit 7 is not a CHR address line")
+
+	m.WritePrg(0x8000, mmc3ChrInversionBit|2)
+	m.WritePrg(0x8001, 0x81)
+	m.WritePrg(0x8000, mmc3ChrInversionBit|5)
+	m.WritePrg(0x8001, 0x84)
+	require.Equal(t, []int{1, 0, 0, 1}, nametablePages(m))
+}
+
+func nametablePages(m NametablePageMapper) []int {
+	pages := make([]int, 4)
+	for quadrant := range pages {
+		pages[quadrant] = m.NametablePage(0x2000 + uint16(quadrant)*nametablePageSize)
+	}
+	return pages
+}
+
+func TestTqromChrRam(t *testing.T) {
+	m := newINES119(newMMC3TestRom(64), &Header{}).(*tqrom)
+	m.WritePrg(0x8000, 2)
+	m.WritePrg(0x8001, 5)
+	m.WritePrg(0x8000, 3)
+	m.WritePrg(0x8001, tqromChrRamBit|1)
+
+	m.WriteChr(0x1000, 0xAA)
+	m.WriteChr(0x1400, 0x55)
+	require.Equal(t, uint8(5), m.ReadChr(0x1000), "CHR-ROM is not writable")
+	require.Equal(t, uint8(0x55), m.ReadChr(0x1400))
+
+	m.WritePrg(0x8000, 2)
+	m.WritePrg(0x8001, tqromChrRamBit|1)
+	require.Equal(t, uint8(0x55), m.ReadChr(0x1000))
+}
+
+func TestMMC3ProgramRomOffset(t *testing.T) {
+	m := newINES4(newMMC3TestRom(8), &Header{}).(*mmc3)
+	m.WritePrg(0x8000, 6)
+	m.WritePrg(0x8001, 1)
+
+	tests := []struct {
+		addr       uint16
+		wantOffset int
+		wantOk     bool
+	}{
+		{addr: 0x6000, wantOk: false},
+		{addr: 0x8001, wantOffset: prgBank8kSize + 1, wantOk: true},
+		{addr: 0xC000, wantOffset: 2 * prgBank8kSize, wantOk: true},
+		{addr: 0xFFFF, wantOffset: 4*prgBank8kSize - 1, wantOk: true},
+	}
+	for _, test := range tests {
+		offset, ok := m.ProgramRomOffset(test.addr)
+		require.Equal(t, test.wantOk, ok)
+		require.Equal(t, test.wantOffset, offset)
+	}
+}
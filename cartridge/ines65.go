@@ -0,0 +1,106 @@
+package cartridge
+
+const (
+	iremH3001MirroringBit = 0b10000000
+	iremH3001IrqEnableBit = 0b10000000
+)
+
+// iremH3001 implements the Irem H3001 board (mapper 65), whose IRQ is a
+// 16-bit counter decremented on every CPU cycle.
+type iremH3001 struct {
+	rom        *Rom
+	prgBanks   [3]int
+	chrBanks   [8]int
+	mirroring  MirroringType
+	irqEnabled bool
+	irqCounter uint16
+	irqReload  uint16
+	irq        bool
+}
+
+func newINES65(rom *Rom, headers *Header) Mapper {
+	return &iremH3001{
+		rom:       rom,
+		prgBanks:  [3]int{0x00, 0x01, 0xFE},
+		mirroring: headers.Mirroring,
+	}
+}
+
+func (m *iremH3001) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *iremH3001) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	window := (addr - 0x8000) / prgBank8kSize
+	bank := prgBank8kCount(m.rom) - 1
+	if int(window) < len(m.prgBanks) {
+		bank = m.prgBanks[window]
+	}
+	return readPrg8k(m.rom, bank, addr)
+}
+
+func (m *iremH3001) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xF000 {
+	case 0x8000:
+		m.prgBanks[0] = int(data)
+	case 0xA000:
+		m.prgBanks[1] = int(data)
+	case 0xC000:
+		m.prgBanks[2] = int(data)
+	case 0xB000:
+		m.chrBanks[addr&0b111] = int(data)
+	case 0x9000:
+		m.writeControl(addr&0b111, data)
+	}
+}
+
+func (m *iremH3001) writeControl(register uint16, data uint8) {
+	switch register {
+	case 1:
+		m.mirroring = VerticalMirroring
+		if data&iremH3001MirroringBit != 0 {
+			m.mirroring = HorizontalMirroring
+		}
+	case 3:
+		m.irqEnabled = data&iremH3001IrqEnableBit != 0
+		m.irq = false
+	case 4:
+		m.irqCounter = m.irqReload
+		m.irq = false
+	case 5:
+		m.irqReload = m.irqReload&0x00FF | uint16(data)<<8
+	case 6:
+		m.irqReload = m.irqReload&0xFF00 | uint16(data)
+	}
+}
+
+func (m *iremH3001) ClockCPU() {
+	if !m.irqEnabled || m.irqCounter == 0 {
+		return
+	}
+	m.irqCounter--
+	if m.irqCounter == 0 {
+		m.irq = true
+		m.irqEnabled = false
+	}
+}
+
+func (m *iremH3001) IRQ() bool {
+	return m.irq
+}
+
+func (m *iremH3001) chrAddress(addr uint16) int {
+	bank := m.chrBanks[addr/chrBank1kSize]
+	return bankAddress(m.rom.Character.Size(), bank, chrBank1kSize, addr)
+}
+
+func (m *iremH3001) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *iremH3001) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
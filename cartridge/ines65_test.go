@@ -0,0 +1,39 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIremH3001Banks(t *testing.T) {
+	m := newINES65(newBankedTestRom(16, 16), &Header{Mirroring: VerticalMirroring})
+	require.Equal(t, []uint8{0, 1, 14, 15}, prgBanks(m), "bank $FE wraps to the second to last one")
+
+	writeMapper(m, []mapperWrite{
+		{0x8000, 3}, {0xA000, 4}, {0xC000, 5}, {0x9001, iremH3001MirroringBit},
+		{0xB000, 8}, {0xB001, 9}, {0xB002, 10}, {0xB003, 11},
+		{0xB004, 12}, {0xB005, 13}, {0xB006, 14}, {0xB007, 15},
+	})
+	require.Equal(t, []uint8{3, 4, 5, 15}, prgBanks(m))
+	require.Equal(t, []uint8{8, 9, 10, 11, 12, 13, 14, 15}, chrBanks(m))
+	require.Equal(t, HorizontalMirroring, m.Mirroring())
+}
+
+func TestIremH3001Irq(t *testing.T) {
+	m := newINES65(newBankedTestRom(16, 16), &Header{}).(*iremH3001)
+	writeMapper(m, []mapperWrite{
+		{0x9005, 0x00}, {0x9006, 0x03}, {0x9003, iremH3001IrqEnableBit}, {0x9004, 0},
+	})
+
+	var irqs []bool
+	for range 4 {
+		m.ClockCPU()
+		irqs = append(irqs, m.IRQ())
+	}
+	require.Equal(t, []bool{false, false, true, true}, irqs)
+	require.False(t, m.irqEnabled, "the counter stops at zero")
+
+	m.WritePrg(0x9003, 0)
+	require.False(t, m.IRQ())
+}
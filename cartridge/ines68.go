@@ -0,0 +1,106 @@
+package cartridge
+
+const (
+	sunsoft4NametableBankBit   = 0b10000000
+	sunsoft4MirroringMask      = 0b00000011
+	sunsoft4ChrNametablesBit   = 0b00010000
+	sunsoft4PrgBankMask        = 0b00001111
+	sunsoft4RamEnableBit       = 0b00010000
+	sunsoft4NametableBankCount = 2
+)
+
+// sunsoft4 implements the Sunsoft-4 board (mapper 68). Besides the usual
+// CHR banking, it can replace CIRAM with 1KB pages of CHR-ROM, which After
+// Burner uses to draw its backgrounds straight from rom.
+type sunsoft4 struct {
+	rom            *Rom
+	chrBanks       [4]int
+	nametableBanks [sunsoft4NametableBankCount]int
+	mirroring      MirroringType
+	chrNametables  bool
+	prgBank        int
+	ramEnabled     bool
+}
+
+func newINES68(rom *Rom, headers *Header) Mapper {
+	return &sunsoft4{
+		rom:       rom,
+		mirroring: headers.Mirroring,
+	}
+}
+
+func (m *sunsoft4) Mirroring() MirroringType {
+	return m.mirroring
+}
+
+func (m *sunsoft4) ReadPrg(addr uint16) uint8 {
+	if addr < 0x8000 {
+		return 0
+	}
+	bank := len(m.rom.Program)/prgBankSize - 1
+	if addr < 0xC000 {
+		bank = m.prgBank
+	}
+	return m.rom.Program[bankAddress(len(m.rom.Program), bank, prgBankSize, addr)]
+}
+
+func (m *sunsoft4) WritePrg(addr uint16, data uint8) {
+	switch addr & 0xF000 {
+	case 0x8000, 0x9000, 0xA000, 0xB000:
+		m.chrBanks[(addr-0x8000)>>12] = int(data)
+	case 0xC000:
+		// only the upper half of the CHR-ROM can be used as nametables
+		m.nametableBanks[0] = int(data | sunsoft4NametableBankBit)
+	case 0xD000:
+		m.nametableBanks[1] = int(data | sunsoft4NametableBankBit)
+	case 0xE000:
+		m.chrNametables = data&sunsoft4ChrNametablesBit != 0
+		switch data & sunsoft4MirroringMask {
+		case 0:
+			m.mirroring = VerticalMirroring
+		case 1:
+			m.mirroring = HorizontalMirroring
+		case 2:
+			m.mirroring = SingleScreenLowerMirroring
+		case 3:
+			m.mirroring = SingleScreenUpperMirroring
+		}
+	case 0xF000:
+		m.prgBank = int(data & sunsoft4PrgBankMask)
+		m.ramEnabled = data&sunsoft4RamEnableBit != 0
+	}
+}
+
+func (m *sunsoft4) ProgramRamReadable() bool {
+	return m.ramEnabled
+}
+
+func (m *sunsoft4) ProgramRamWritable() bool {
+	return m.ramEnabled
+}
+
+func (m *sunsoft4) chrAddress(addr uint16) int {
+	bank := m.chrBanks[addr/chrBank2kSize]
+	return bankAddress(m.rom.Character.Size(), bank, chrBank2kSize, addr)
+}
+
+func (m *sunsoft4) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(m.chrAddress(addr))
+}
+
+func (m *sunsoft4) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(m.chrAddress(addr), data)
+}
+
+func (m *sunsoft4) ReadNametable(addr uint16) (uint8, bool) {
+	if !m.chrNametables {
+		return 0, false
+	}
+	page := m.mirroring.nametablePage(addr)
+	bank := m.nametableBanks[page%sunsoft4NametableBankCount]
+	return m.rom.Character.Read(bankAddress(m.rom.Character.Size(), bank, chrBank1kSize, addr)), true
+}
+
+func (m *sunsoft4) WriteNametable(_ uint16, _ uint8) bool {
+	return m.chrNametables
+}
@@ -0,0 +1,44 @@
+package cartridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSunsoft4Banks(t *testing.T) {
+	m := newINES68(newBankedTestRom(32, 256), &Header{}).(*sunsoft4)
+	writeMapper(m, []mapperWrite{
+		{0x8000, 1}, {0x9000, 2}, {0xA000, 3}, {0xB000, 4},
+		{0xF000, sunsoft4RamEnableBit | 3},
+	})
+
+	require.Equal(t, []uint8{6, 7, 30, 31}, prgBanks(m))
+	require.Equal(t, []uint8{2, 3, 4, 5, 6, 7, 8, 9}, chrBanks(m))
+	require.True(t, m.ProgramRamReadable())
+
+	m.WritePrg(0xF000, 3)
+	require.False(t, m.ProgramRamWritable())
+}
+
+func TestSunsoft4ChrNametables(t *testing.T) {
+	m := newINES68(newBankedTestRom(32, 256), &Header{}).(*sunsoft4)
+	m.WritePrg(0xC000, 3)
+	m.WritePrg(0xD000, 5)
+
+	_, ok := m.ReadNametable(0x2000)
+	require.False(t, ok, "CIRAM is used until the CHR-ROM nametables are enabled")
+
+	m.WritePrg(0xE000, sunsoft4ChrNametablesBit|1)
+	require.Equal(t, HorizontalMirroring, m.Mirroring())
+	pages := make([]uint8, 4)
+	for quadrant := range pages {
+		data, ok := m.ReadNametable(0x2000 + uint16(quadrant)*nametablePageSize)
+		require.True(t, ok)
+		pages[quadrant] = data
+	}
+	require.Equal(t, []uint8{0x83, 0x83, 0x85, 0x85}, pages, "only the upper half of the CHR-ROM is used")
+	require.True(t, m.WriteNametable(0x2000, 0xAA), "writes to rom nametables are dropped")
+	data, _ := m.ReadNametable(0x2000)
+	require.Equal(t, uint8(0x83), data)
+}
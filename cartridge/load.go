@@ -0,0 +1,122 @@
+package cartridge
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/LucasWillBlumenau/nes/patch"
+	"github.com/LucasWillBlumenau/nes/poweron"
+)
+
+type loadOptions struct {
+	archiveEntry        string
+	savePath            string
+	withoutGameDatabase bool
+	patches             [][]byte
+	fdsBios             []byte
+	ramState            poweron.RamState
+}
+
+func newLoadOptions(options []Option) loadOptions {
+	var opts loadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// Option customizes how a cartridge is loaded.
+type Option func(*loadOptions)
+
+// WithArchiveEntry picks the archive entry named name instead of the first
+// rom found in it. Plain rom files ignore it.
+func WithArchiveEntry(name string) Option {
+	return func(o *loadOptions) {
+		o.archiveEntry = name
+	}
+}
+
+// WithSavePath sets the file battery backed memory is loaded from and
+// saved to. Cartridges loaded without one are never saved.
+func WithSavePath(path string) Option {
+	return func(o *loadOptions) {
+		o.savePath = path
+	}
+}
+
+// WithoutGameDatabase trusts the rom header as is, instead of correcting it
+// with the game database entry matching the rom checksums.
+func WithoutGameDatabase() Option {
+	return func(o *loadOptions) {
+		o.withoutGameDatabase = true
+	}
+}
+
+// WithPatch applies an IPS, BPS or UPS patch to the rom before it is
+// parsed. Patches given in several options are applied in order.
+func WithPatch(data []byte) Option {
+	return func(o *loadOptions) {
+		o.patches = append(o.patches, data)
+	}
+}
+
+// WithFDSBios sets the disk system bios, the 8KB disksys.rom dump, needed
+// to run disk images.
+func WithFDSBios(data []byte) Option {
+	return func(o *loadOptions) {
+		o.fdsBios = data
+	}
+}
+
+// WithRamState sets the PRG-RAM contents at power on. Battery backed
+// memory is loaded from the save file instead, when there is one.
+func WithRamState(state poweron.RamState) Option {
+	return func(o *loadOptions) {
+		o.ramState = state
+	}
+}
+
+// LoadCartridge loads a rom, or an archive holding one, from reader.
+func LoadCartridge(reader io.Reader, options ...Option) (*Cartridge, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(data, options...)
+}
+
+// FromBytes loads a rom, or an archive holding one, from data.
+func FromBytes(data []byte, options ...Option) (*Cartridge, error) {
+	opts := newLoadOptions(options)
+	data, err := extractRom(data, opts.archiveEntry)
+	if err != nil {
+		return nil, err
+	}
+	for i, patchData := range opts.patches {
+		data, err = patch.Apply(data, patchData)
+		if err != nil {
+			return nil, fmt.Errorf("applying patch %d: %w", i+1, err)
+		}
+	}
+
+	if isFDSImage(data) {
+		headers, rom, err := readFDS(data, opts.fdsBios)
+		if err != nil {
+			return nil, err
+		}
+		return newCartridge(headers, rom, nil, opts)
+	}
+	if bytes.HasPrefix(data, unifMagic) {
+		headers, rom, game, err := readUNIF(data)
+		if err != nil {
+			return nil, err
+		}
+		return newCartridge(headers, rom, game, opts)
+	}
+	headers, rom, err := readINES(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return newCartridge(headers, rom, nil, opts)
+}
@@ -0,0 +1,147 @@
+package cartridge
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/poweron"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRom(mapperId uint8, marker uint8) []byte {
+	rom := append([]byte{}, iNESMagic...)
+	rom = append(rom, 1, 1, mapperId<<4, mapperId&0xF0, 0, 0, 0, 0, 0, 0, 0, 0)
+	program := make([]byte, prgBankSize)
+	program[0] = marker
+	rom = append(rom, program...)
+	return append(rom, make([]byte, chrBankSize)...)
+}
+
+func newTestZip(t *testing.T, entries map[string][]byte, order ...string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, name := range order {
+		entry, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write(entries[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestFromBytes(t *testing.T) {
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	_, err := writer.Write(newTestRom(0, 1))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	entries := map[string][]byte{
+		"readme.txt": []byte("not a rom"),
+		"music.nsf":  []byte("NESM\x1A"),
+		"first.NES":  newTestRom(0, 2),
+		"second.nes": newTestRom(0, 3),
+	}
+	archive := newTestZip(t, entries, "readme.txt", "music.nsf", "first.NES", "second.nes")
+
+	tests := []struct {
+		name       string
+		data       []byte
+		options    []Option
+		wantMarker uint8
+		wantErr    error
+	}{
+		{name: "plain rom", data: newTestRom(0, 4), wantMarker: 4},
+		{name: "gzip", data: gzipped.Bytes(), wantMarker: 1},
+		{name: "zip picks the first rom", data: archive, wantMarker: 2},
+		{name: "zip named entry", data: archive, options: []Option{WithArchiveEntry("second.nes")}, wantMarker: 3},
+		{name: "zip missing entry", data: archive, options: []Option{WithArchiveEntry("third.nes")}, wantErr: ErrInvalidRomFile},
+		{name: "truncated rom", data: newTestRom(0, 0)[:100], wantErr: ErrInvalidRomFile},
+		{name: "not a rom", data: []byte("hello, world!!!!!"), wantErr: ErrInvalidRomFile},
+		{name: "unknown mapper", data: newTestRom(0xFF, 0), wantErr: ErrUnimplementedMapper},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cart, err := FromBytes(test.data, test.options...)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantMarker, cart.ReadPrgRom(0x8000))
+		})
+	}
+}
+
+func TestReadINESShortReads(t *testing.T) {
+	_, rom, err := readINES(&oneByteReader{data: newTestRom(0, 5)})
+	require.NoError(t, err)
+	require.Equal(t, uint8(5), rom.Program[0])
+}
+
+// oneByteReader hands out its data one byte per call, like a slow network
+// connection would.
+type oneByteReader struct {
+	data []byte
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+	return 1, nil
+}
+
+func TestLoadCartridgeFromRomPatches(t *testing.T) {
+	dir := t.TempDir()
+	romPath := filepath.Join(dir, "game.nes")
+	require.NoError(t, os.WriteFile(romPath, newTestRom(0, 1), 0o644))
+	// sets the first PRG byte, right after the 16 byte header
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "game.ips"), []byte("PATCH\x00\x00\x10\x00\x01\x02EOF"), 0o644))
+
+	cart, err := LoadCartridgeFromRom(romPath)
+	require.NoError(t, err)
+	require.Equal(t, uint8(2), cart.ReadPrgRom(0x8000))
+
+	cart, err = LoadCartridgeFromRom(romPath,
+		WithPatch([]byte("PATCH\x00\x00\x10\x00\x01\x03EOF")),
+		WithPatch([]byte("PATCH\x00\x00\x11\x00\x01\x04EOF")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint8(3), cart.ReadPrgRom(0x8000), "given patches replace the same name one")
+	require.Equal(t, uint8(4), cart.ReadPrgRom(0x8001))
+
+	original, err := os.ReadFile(romPath)
+	require.NoError(t, err)
+	require.Equal(t, newTestRom(0, 1), original)
+}
+
+func TestTrainer(t *testing.T) {
+	rom := newTestRom(0, 1)
+	rom[6] |= 0b100
+	trainer := make([]byte, trainerSize)
+	trainer[0] = 0xA9
+	trainer[trainerSize-1] = 0x60
+	rom = append(rom[:16], append(trainer, rom[16:]...)...)
+
+	cart, err := FromBytes(rom, WithRamState(poweron.Ones))
+	require.NoError(t, err)
+	require.True(t, cart.Header().UseTrainer)
+	require.Equal(t, uint8(1), cart.ReadPrgRom(0x8000))
+	require.Equal(t, uint8(0xA9), cart.ReadPrgRom(0x7000))
+	require.Equal(t, uint8(0x60), cart.ReadPrgRom(0x71FF))
+	require.Equal(t, uint8(0xFF), cart.ReadPrgRom(0x6FFF))
+	require.Equal(t, uint8(0xFF), cart.ReadPrgRom(0x7200))
+}
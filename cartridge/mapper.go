@@ -1,9 +1,107 @@
 package cartridge
 
-type mapper interface {
+// Mapper is the board logic of a cartridge, translating the CPU and PPU
+// addresses to the rom and ram chips it carries. ReadPrg and WritePrg get
+// the CPU accesses from $4020 up, except for the PRG-RAM at $6000-$7FFF
+// allocated from the header, which the cartridge serves on its own. Boards
+// with more features implement the optional interfaces below, and are
+// added with RegisterMapper.
+type Mapper interface {
 	Mirroring() MirroringType
 	ReadPrg(addr uint16) uint8
 	WritePrg(addr uint16, data uint8)
 	ReadChr(addr uint16) uint8
 	WriteChr(addr uint16, data uint8)
 }
+
+// PersistentMapper is implemented by boards with memory that survives
+// power-off, such as battery-backed RAM or self-writable flash, which
+// they keep on their own instead of in Rom.ProgramRam. SaveData returns nil
+// while there is nothing worth saving.
+type PersistentMapper interface {
+	SaveData() []byte
+	LoadSaveData(data []byte) error
+}
+
+// ResettableMapper is implemented by boards that react to the console reset
+// button. Boards without it keep their registers across a soft reset.
+type ResettableMapper interface {
+	Reset()
+}
+
+// CPUClockedMapper is implemented by boards with logic driven by the CPU
+// clock, such as cycle based IRQ counters.
+type CPUClockedMapper interface {
+	ClockCPU()
+}
+
+// ScanlineMapper is implemented by boards that count rendered scanlines by
+// watching the PPU fetch pattern data.
+type ScanlineMapper interface {
+	ClockScanline()
+}
+
+// PPUAddressMapper is implemented by boards that watch the PPU address bus,
+// like the ones clocking IRQ counters from the A12 line. It gets every
+// address the PPU reads or writes, before the access is done.
+type PPUAddressMapper interface {
+	SnoopPPUAddress(addr uint16)
+}
+
+// IRQMapper is implemented by boards that can hold the CPU IRQ line.
+type IRQMapper interface {
+	IRQ() bool
+}
+
+// NametableMapper is implemented by boards that can take over the PPU
+// nametable space instead of letting it resolve to the console CIRAM. Both
+// methods report whether the access was handled by the board.
+type NametableMapper interface {
+	ReadNametable(addr uint16) (uint8, bool)
+	WriteNametable(addr uint16, data uint8) bool
+}
+
+// NametablePageMapper is implemented by boards that pick the nametable page
+// of each quadrant on their own, like TxSROM driving CIRAM A10 from the CHR
+// banks, instead of following one of the fixed mirroring arrangements.
+type NametablePageMapper interface {
+	NametablePage(addr uint16) int
+}
+
+// ProgramRamMapper is implemented by boards that can disable or write
+// protect the PRG-RAM at $6000-$7FFF, which is always accessible otherwise.
+type ProgramRamMapper interface {
+	ProgramRamReadable() bool
+	ProgramRamWritable() bool
+}
+
+// DiskMapper is implemented by disk drives, where the player swaps the
+// disk sides while playing.
+type DiskMapper interface {
+	InsertDisk(side int) error
+	EjectDisk()
+	SwitchDiskSide() error
+	DiskSides() int
+}
+
+// AudioMapper is implemented by boards with their own sound channels,
+// which the console mixes with its own.
+type AudioMapper interface {
+	AudioSample() float32
+}
+
+// ProgramRomMapper is implemented by boards that can tell where in the
+// program rom a CPU address is mapped, which debugging tools use to show
+// the bank the code runs from. The second result is false for addresses
+// outside of the program rom.
+type ProgramRomMapper interface {
+	ProgramRomOffset(addr uint16) (int, bool)
+}
+
+// StatefulMapper is implemented by boards that can serialize their
+// registers, so a running game can be saved and restored later. The memory
+// in Rom is saved by the caller and is left out of the state.
+type StatefulMapper interface {
+	SaveState() ([]byte, error)
+	LoadState(data []byte) error
+}
@@ -1,10 +1,73 @@
 package cartridge
 
-const lastMapperId = 232
+import "sync"
 
-type createMapperFn func(rom *cartridgeRom, headers *cartridgeHeaders) mapper
+// MapperFactory builds the mapper of a cartridge from its rom and header.
+// The rom memory is already allocated, with the PRG-RAM and CHR-RAM sizes
+// the header asks for.
+type MapperFactory func(rom *Rom, headers *Header) Mapper
 
-var mappers = [lastMapperId + 1]createMapperFn{
-	0: newINES0,
-	2: newINES2,
+// AnySubmapper registers a mapper for every submapper of its mapper number
+// that has no registration of its own.
+const AnySubmapper = -1
+
+type mapperKey struct {
+	id        int
+	submapper int
+}
+
+var mappers = map[int]MapperFactory{
+	0:   newINES0,
+	2:   newINES2,
+	4:   newINES4,
+	18:  newINES18,
+	20:  newINES20,
+	28:  newINES28,
+	30:  newINES30,
+	32:  newINES32,
+	33:  newINES33,
+	48:  newINES48,
+	65:  newINES65,
+	68:  newINES68,
+	94:  newINES94,
+	118: newINES118,
+	119: newINES119,
+	180: newINES180,
+	225: newINES225,
+	226: newINES226,
+	227: newINES227,
+	228: newINES228,
+}
+
+var (
+	registeredMappersMu sync.RWMutex
+	registeredMappers   = map[mapperKey]MapperFactory{}
+)
+
+// RegisterMapper makes factory build the cartridges with the given mapper
+// and submapper numbers, taking precedence over the built-in boards. Pass
+// AnySubmapper to handle every submapper. Registering the same numbers
+// again replaces the previous factory.
+func RegisterMapper(id int, submapper int, factory MapperFactory) {
+	if factory == nil {
+		panic("cartridge: RegisterMapper factory is nil")
+	}
+	registeredMappersMu.Lock()
+	defer registeredMappersMu.Unlock()
+	registeredMappers[mapperKey{id, submapper}] = factory
+}
+
+// findMapper returns the factory for a header, preferring the one
+// registered for its exact submapper.
+func findMapper(headers *Header) (MapperFactory, bool) {
+	registeredMappersMu.RLock()
+	defer registeredMappersMu.RUnlock()
+	if factory, ok := registeredMappers[mapperKey{headers.MapperId, headers.Submapper}]; ok {
+		return factory, true
+	}
+	if factory, ok := registeredMappers[mapperKey{headers.MapperId, AnySubmapper}]; ok {
+		return factory, true
+	}
+	factory, ok := mappers[headers.MapperId]
+	return factory, ok
 }
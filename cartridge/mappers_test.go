@@ -0,0 +1,112 @@
+package cartridge_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cartridge"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureMapper answers every PRG read with its marker and records the PPU
+// addresses it sees.
+type fixtureMapper struct {
+	marker  uint8
+	snooped []uint16
+	rom     *cartridge.Rom
+}
+
+func (m *fixtureMapper) Mirroring() cartridge.MirroringType {
+	return cartridge.VerticalMirroring
+}
+
+func (m *fixtureMapper) ReadPrg(addr uint16) uint8 {
+	return m.marker
+}
+
+func (m *fixtureMapper) WritePrg(addr uint16, data uint8) {}
+
+func (m *fixtureMapper) ReadChr(addr uint16) uint8 {
+	return m.rom.Character.Read(int(addr))
+}
+
+func (m *fixtureMapper) WriteChr(addr uint16, data uint8) {
+	m.rom.Character.Write(int(addr), data)
+}
+
+func (m *fixtureMapper) SnoopPPUAddress(addr uint16) {
+	m.snooped = append(m.snooped, addr)
+}
+
+// audioFixtureMapper is a fixture with a sound channel stuck at level.
+type audioFixtureMapper struct {
+	fixtureMapper
+	level float32
+}
+
+func (m *audioFixtureMapper) AudioSample() float32 {
+	return m.level
+}
+
+func fixtureFactory(marker uint8) cartridge.MapperFactory {
+	return func(rom *cartridge.Rom, headers *cartridge.Header) cartridge.Mapper {
+		return &fixtureMapper{marker: marker, rom: rom}
+	}
+}
+
+// newNES2Rom builds a NES 2.0 rom with one PRG bank and 8KB of CHR-RAM.
+func newNES2Rom(mapperId int, submapper int) []byte {
+	rom := []byte{'N', 'E', 'S', 0x1A, 1, 0,
+		uint8(mapperId<<4) & 0xF0, uint8(mapperId&0xF0) | 0b1000,
+		uint8(submapper<<4) | uint8(mapperId>>8), 0, 0, 0x07, 0, 0, 0, 0}
+	return append(rom, make([]byte, 16*1024)...)
+}
+
+func TestRegisterMapper(t *testing.T) {
+	cartridge.RegisterMapper(3000, cartridge.AnySubmapper, fixtureFactory(1))
+	cartridge.RegisterMapper(3000, 2, fixtureFactory(2))
+	cartridge.RegisterMapper(0, 5, fixtureFactory(3))
+
+	tests := []struct {
+		name       string
+		mapperId   int
+		submapper  int
+		wantMarker uint8
+	}{
+		{name: "any submapper", mapperId: 3000, submapper: 1, wantMarker: 1},
+		{name: "exact submapper", mapperId: 3000, submapper: 2, wantMarker: 2},
+		{name: "overrides a built-in board", mapperId: 0, submapper: 5, wantMarker: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cart, err := cartridge.FromBytes(newNES2Rom(test.mapperId, test.submapper), cartridge.WithoutGameDatabase())
+			require.NoError(t, err)
+			require.Equal(t, test.wantMarker, cart.ReadPrgRom(0x8000))
+
+			cart.WriteChrRom(0x0010, 0x42)
+			require.Equal(t, uint8(0x42), cart.ReadChrRom(0x0010))
+			cart.SnoopPPUAddress(0x1000)
+			require.Equal(t, []uint16{0x1000}, cart.Mapper().(*fixtureMapper).snooped)
+		})
+	}
+
+	cart, err := cartridge.FromBytes(newNES2Rom(0, 0), cartridge.WithoutGameDatabase())
+	require.NoError(t, err)
+	require.Zero(t, cart.ReadPrgRom(0x8000), "other submappers keep the built-in board")
+	_, err = cart.MapperState()
+	require.ErrorIs(t, err, cartridge.ErrStatelessMapper)
+}
+
+func TestAudioMapper(t *testing.T) {
+	cartridge.RegisterMapper(3001, cartridge.AnySubmapper, func(rom *cartridge.Rom, headers *cartridge.Header) cartridge.Mapper {
+		return &audioFixtureMapper{fixtureMapper: fixtureMapper{rom: rom}, level: 0.5}
+	})
+
+	cart, err := cartridge.FromBytes(newNES2Rom(3001, 0), cartridge.WithoutGameDatabase())
+	require.NoError(t, err)
+	require.Equal(t, float32(0.5), cart.AudioSample())
+
+	cart, err = cartridge.FromBytes(newNES2Rom(0, 0), cartridge.WithoutGameDatabase())
+	require.NoError(t, err)
+	require.Zero(t, cart.AudioSample(), "boards without sound channels are silent")
+}
@@ -0,0 +1,172 @@
+package cartridge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	unifHeaderSize      = 32
+	unifChunkHeaderSize = 8
+	unifRomChunks       = 16
+)
+
+const (
+	unifMirroringHorizontal = iota
+	unifMirroringVertical
+	unifMirroringSingleScreenLower
+	unifMirroringSingleScreenUpper
+	unifMirroringFourScreen
+	unifMirroringMapperControlled
+)
+
+var unifMagic = []byte("UNIF")
+
+// unifBoardPrefixes are the manufacturer prefixes of UNIF board names,
+// which do not change the board a name refers to.
+var unifBoardPrefixes = []string{"NES-", "HVC-", "UNL-", "BTL-", "BMC-", "MLT-"}
+
+type unifBoard struct {
+	mapperId  int
+	submapper int
+}
+
+// unifBoards maps UNIF board names, without their prefix, to the mapper
+// implementing them.
+var unifBoards = map[string]unifBoard{
+	"NROM":             {mapperId: 0},
+	"NROM-128":         {mapperId: 0},
+	"NROM-256":         {mapperId: 0},
+	"RROM":             {mapperId: 0},
+	"RROM-128":         {mapperId: 0},
+	"UNROM":            {mapperId: 2},
+	"UOROM":            {mapperId: 2},
+	"TBROM":            {mapperId: 4},
+	"TEROM":            {mapperId: 4},
+	"TFROM":            {mapperId: 4},
+	"TGROM":            {mapperId: 4},
+	"TKROM":            {mapperId: 4},
+	"TLROM":            {mapperId: 4},
+	"TR1ROM":           {mapperId: 4},
+	"TSROM":            {mapperId: 4},
+	"TVROM":            {mapperId: 4},
+	"UNROM-512-8":      {mapperId: 30},
+	"UNROM-512-16":     {mapperId: 30},
+	"UNROM-512-32":     {mapperId: 30},
+	"NTBROM":           {mapperId: 68},
+	"TKSROM":           {mapperId: 118},
+	"TLSROM":           {mapperId: 118},
+	"TQROM":            {mapperId: 119},
+	"42in1ResetSwitch": {mapperId: 226},
+	"ACTION52":         {mapperId: 228},
+}
+
+// readUNIF reads a rom in the UNIF format, where the board is given by
+// name in a chunk instead of by mapper number.
+func readUNIF(data []byte) (*Header, *Rom, *GameInfo, error) {
+	if len(data) < unifHeaderSize {
+		return nil, nil, nil, ErrInvalidRomFile
+	}
+	chunks := make(map[string][]byte)
+	for offset := unifHeaderSize; offset < len(data); {
+		if len(data)-offset < unifChunkHeaderSize {
+			return nil, nil, nil, ErrInvalidRomFile
+		}
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		offset += unifChunkHeaderSize
+		if size > len(data)-offset {
+			return nil, nil, nil, ErrInvalidRomFile
+		}
+		chunks[id] = data[offset : offset+size]
+		offset += size
+	}
+
+	boardName, ok := chunks["MAPR"]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: UNIF file has no board", ErrInvalidRomFile)
+	}
+	board := unifString(boardName)
+	unifBoard, ok := unifBoards[trimUnifBoardPrefix(board)]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: board %s not implemented", ErrUnimplementedMapper, board)
+	}
+
+	var program, character []byte
+	for i := range unifRomChunks {
+		program = append(program, chunks[fmt.Sprintf("PRG%X", i)]...)
+		character = append(character, chunks[fmt.Sprintf("CHR%X", i)]...)
+	}
+	if len(program) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: UNIF file has no PRG-ROM", ErrInvalidRomFile)
+	}
+
+	headers := &Header{
+		ProgramRomSize:         len(program),
+		ProgramBanksQuantity:   max(len(program)/prgBankSize, 1),
+		CharacterRomSize:       len(character),
+		CharacterBanksQuantity: len(character) / chrBankSize,
+		MapperId:               unifBoard.mapperId,
+		Submapper:              unifBoard.submapper,
+		ProgramRamSize:         programRamBankSize,
+		Mirroring:              HorizontalMirroring,
+		SolderedMirroring:      HorizontalMirroring,
+	}
+	if len(character) == 0 {
+		headers.UseCharacterRam = true
+		headers.CharacterBanksQuantity = 1
+		headers.CharacterRamSize = defaultCharacterRamSize
+	}
+	if battery, ok := chunks["BATR"]; ok && len(battery) > 0 && battery[0] != 0 {
+		headers.UseBatteryBackedRam = true
+	}
+	if timing, ok := chunks["TVCI"]; ok && len(timing) > 0 {
+		headers.Timing = TimingMode(timing[0]) & timingMask
+	}
+	if mirroring, ok := chunks["MIRR"]; ok && len(mirroring) > 0 {
+		applyUnifMirroring(headers, mirroring[0])
+	}
+
+	rom := &Rom{
+		Program:   program,
+		Character: CharacterRom(character),
+	}
+	game := &GameInfo{Title: unifString(chunks["NAME"]), Board: board}
+	return headers, rom, game, nil
+}
+
+func applyUnifMirroring(headers *Header, mirroring uint8) {
+	switch mirroring {
+	case unifMirroringHorizontal:
+		headers.Mirroring = HorizontalMirroring
+	case unifMirroringVertical:
+		headers.Mirroring = VerticalMirroring
+		headers.SolderedMirroring = VerticalMirroring
+	case unifMirroringSingleScreenLower:
+		headers.Mirroring = SingleScreenLowerMirroring
+	case unifMirroringSingleScreenUpper:
+		headers.Mirroring = SingleScreenUpperMirroring
+	case unifMirroringFourScreen:
+		headers.Mirroring = FourScreenMirroring
+		headers.AlternativeNametables = true
+	}
+}
+
+// unifString decodes the null terminated strings of UNIF chunks.
+func unifString(data []byte) string {
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		data = data[:end]
+	}
+	return string(data)
+}
+
+func trimUnifBoardPrefix(board string) string {
+	for _, prefix := range unifBoardPrefixes {
+		if trimmed, ok := strings.CutPrefix(board, prefix); ok {
+			return trimmed
+		}
+	}
+	return board
+}
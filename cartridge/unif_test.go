@@ -0,0 +1,117 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUnif(chunks ...any) []byte {
+	data := append([]byte("UNIF"), make([]byte, unifHeaderSize-4)...)
+	for i := 0; i < len(chunks); i += 2 {
+		chunk := chunks[i+1].([]byte)
+		data = append(data, chunks[i].(string)...)
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(chunk)))
+		data = append(data, chunk...)
+	}
+	return data
+}
+
+func TestReadUnif(t *testing.T) {
+	program := make([]byte, 2*prgBankSize)
+	program[0] = 0x12
+	character := make([]byte, chrBankSize)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		wantMapper    int
+		wantMirroring MirroringType
+		wantErr       error
+	}{
+		{
+			name: "prefixed board",
+			data: newTestUnif(
+				"MAPR", []byte("NES-NROM-256\x00"),
+				"NAME", []byte("Test Game\x00"),
+				"PRG0", program,
+				"CHR0", character,
+				"MIRR", []byte{unifMirroringVertical},
+			),
+			wantMapper:    0,
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name: "board with chr ram",
+			data: newTestUnif(
+				"MAPR", []byte("UNL-TLSROM\x00"),
+				"PRG0", program,
+			),
+			wantMapper:    118,
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name: "multicart board",
+			data: newTestUnif(
+				"MAPR", []byte("BMC-42in1ResetSwitch\x00"),
+				"PRG0", program,
+				"MIRR", []byte{unifMirroringVertical},
+			),
+			wantMapper:    226,
+			wantMirroring: VerticalMirroring,
+		},
+		{
+			name: "Active Enterprises board",
+			data: newTestUnif(
+				"MAPR", []byte("MLT-ACTION52\x00"),
+				"PRG0", program,
+				"CHR0", character,
+			),
+			wantMapper:    228,
+			wantMirroring: HorizontalMirroring,
+		},
+		{
+			name:    "unknown board",
+			data:    newTestUnif("MAPR", []byte("UNL-UNKNOWN\x00"), "PRG0", program),
+			wantErr: ErrUnimplementedMapper,
+		},
+		{
+			name:    "truncated chunk",
+			data:    newTestUnif("MAPR", []byte("NES-NROM-256\x00"), "PRG0", program)[:100],
+			wantErr: ErrInvalidRomFile,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			headers, rom, game, err := readUNIF(test.data)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantMapper, headers.MapperId)
+			require.Equal(t, test.wantMirroring, headers.Mirroring)
+			require.Equal(t, 2, headers.ProgramBanksQuantity)
+			require.Equal(t, program, rom.Program)
+			require.NotEmpty(t, game.Board)
+		})
+	}
+}
+
+func TestFromBytesUnif(t *testing.T) {
+	program := make([]byte, prgBankSize)
+	program[0] = 0x34
+	cart, err := FromBytes(newTestUnif(
+		"MAPR", []byte("NES-NROM-128\x00"),
+		"NAME", []byte("Test Game\x00"),
+		"PRG0", program,
+		"CHR0", make([]byte, chrBankSize),
+	))
+	require.NoError(t, err)
+	require.Equal(t, uint8(0x34), cart.ReadPrgRom(0xC000))
+	game, ok := cart.Game()
+	require.True(t, ok)
+	require.Equal(t, GameInfo{Title: "Test Game", Board: "NES-NROM-128"}, game)
+}
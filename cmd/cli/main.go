@@ -1,12 +1,21 @@
 package main
 
 import (
+	"bufio"
+	"flag"
+	"fmt"
 	"image"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/LucasWillBlumenau/nes/cartridge"
 	"github.com/LucasWillBlumenau/nes/joypad"
 	"github.com/LucasWillBlumenau/nes/nes"
+	"github.com/LucasWillBlumenau/nes/poweron"
+	"github.com/LucasWillBlumenau/nes/trace"
 	"github.com/LucasWillBlumenau/nes/window"
 )
 
@@ -17,19 +26,43 @@ const (
 
 func main() {
 	frames := make(chan image.RGBA)
-	cartPath := readCliArgs()
+	args := readCliArgs()
 	joypadOne := joypad.New()
 	joypadTwo := joypad.New()
 	scaleFactor := 2
-	nes, err := nes.NewNES(
+	cart, err := cartridge.LoadCartridgeFromRom(args.romPath, args.cartOptions...)
+	if err != nil {
+		panic(err)
+	}
+	if cart.DiskSides() > 0 {
+		if err := cart.InsertDisk(args.diskSide - 1); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if game, ok := cart.Game(); ok {
+		log.Printf("loaded %s (%s)\n", game.Title, game.Board)
+	}
+	nes := nes.NewNES(
 		frames,
-		cartPath,
+		cart,
 		scaleFactor,
 		joypadOne,
 		joypadTwo,
+		args.ramState,
 	)
-	if err != nil {
-		panic(err)
+	if args.tracePath != "" {
+		traceFile, err := createTraceFile(args.tracePath)
+		if err != nil {
+			log.Fatalf("error creating trace: %s\n", err)
+		}
+		traceWriter := bufio.NewWriter(traceFile)
+		nes.Trace(traceWriter, args.traceOptions)
+		defer func() {
+			if err := traceWriter.Flush(); err != nil {
+				log.Printf("error writing trace: %s\n", err)
+			}
+			traceFile.Close()
+		}()
 	}
 
 	window := window.NewWindow(
@@ -39,14 +72,144 @@ func main() {
 		joypadTwo,
 		frames,
 	)
+	window.BindConsole(nes)
 	go nes.Run()
 	window.Show()
+	if err := nes.Close(); err != nil {
+		log.Printf("error saving game: %s\n", err)
+	}
+}
+
+// createTraceFile opens the file the trace is written to, where - is the
+// standard output.
+func createTraceFile(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+// nopCloser keeps the standard output open when the trace is closed.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// parseRange parses a range of numbers written as first-last, or a single
+// number for a range without an end, where last is zero.
+func parseRange(value string, base int, bitSize int) (first uint64, last uint64, err error) {
+	firstValue, lastValue, hasLast := strings.Cut(value, "-")
+	first, err = strconv.ParseUint(trimHexPrefix(firstValue, base), base, bitSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q: %w", value, err)
+	}
+	if !hasLast {
+		return first, 0, nil
+	}
+	last, err = strconv.ParseUint(trimHexPrefix(lastValue, base), base, bitSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q: %w", value, err)
+	}
+	if last < first {
+		return 0, 0, fmt.Errorf("invalid range %q: the end comes before the start", value)
+	}
+	return first, last, nil
+}
+
+// trimHexPrefix removes the $ or 0x hexadecimal numbers are usually written
+// with.
+func trimHexPrefix(value string, base int) string {
+	if base != 16 {
+		return value
+	}
+	value = strings.TrimPrefix(value, "$")
+	return strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+}
+
+// patchPaths collects the -patch flags, which can be repeated to stack
+// several patches.
+type patchPaths []string
+
+func (p *patchPaths) String() string {
+	return strings.Join(*p, ",")
 }
 
-func readCliArgs() string {
-	args := os.Args[1:]
+func (p *patchPaths) Set(path string) error {
+	*p = append(*p, path)
+	return nil
+}
+
+// cliArgs holds the rom path and the settings given in the command line.
+type cliArgs struct {
+	romPath     string
+	cartOptions []cartridge.Option
+	diskSide    int
+	ramState    poweron.RamState
+	// tracePath is where the trace is written, when it isn't empty
+	tracePath    string
+	traceOptions trace.Options
+}
+
+func readCliArgs() cliArgs {
+	noGameDatabase := flag.Bool("no-gamedb", false, "trust the rom header instead of the game database")
+	fdsBiosPath := flag.String("fds-bios", "", "disk system bios, defaults to disksys.rom next to the disk image")
+	var patches patchPaths
+	flag.Var(&patches, "patch", "IPS, BPS or UPS patch to apply, can be repeated")
+	diskSide := flag.Int("disk-side", 1, "disk side inserted at power on, for disk system images")
+	var ramState poweron.RamState
+	flag.TextVar(&ramState, "ram-init", poweron.Zeros, "RAM contents at power on: zeros, ones, random or pattern")
+	tracePath := flag.String("trace", "", "file the executed instructions are written to, or - for the standard output")
+	var traceOptions trace.Options
+	flag.Func("trace-pc", "hexadecimal address range traced, like C000-FFFF", func(value string) error {
+		first, last, err := parseRange(value, 16, 16)
+		traceOptions.FirstPc, traceOptions.LastPc = uint16(first), uint16(last)
+		return err
+	})
+	flag.Func("trace-frames", "frames traced, like 60-120, or 60 to trace from frame 60 on", func(value string) error {
+		first, last, err := parseRange(value, 10, 64)
+		traceOptions.StartFrame = first
+		if last != 0 {
+			// the range includes the last frame
+			traceOptions.StopFrame = last + 1
+		}
+		return err
+	})
+	flag.Int64Var(&traceOptions.SkipInstructions, "trace-skip", 0, "instructions run before the trace starts")
+	flag.Int64Var(&traceOptions.MaxInstructions, "trace-count", 0, "instructions traced, or 0 for no limit")
+	flag.Parse()
+
+	args := flag.Args()
 	if len(args) != 1 {
 		log.Fatalln("the program only supports a rom path as argument")
 	}
-	return args[0]
+	var options []cartridge.Option
+	if *noGameDatabase {
+		options = append(options, cartridge.WithoutGameDatabase())
+	}
+	for _, path := range patches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("error reading patch: %s\n", err)
+		}
+		options = append(options, cartridge.WithPatch(data))
+	}
+	if *fdsBiosPath != "" {
+		data, err := os.ReadFile(*fdsBiosPath)
+		if err != nil {
+			log.Fatalf("error reading disk system bios: %s\n", err)
+		}
+		options = append(options, cartridge.WithFDSBios(data))
+	}
+	options = append(options, cartridge.WithRamState(ramState))
+	return cliArgs{
+		romPath:      args[0],
+		cartOptions:  options,
+		diskSide:     *diskSide,
+		ramState:     ramState,
+		tracePath:    *tracePath,
+		traceOptions: traceOptions,
+	}
 }
@@ -0,0 +1,175 @@
+// Command gamedb converts the NES 2.0 XML database by NewRisingSun
+// (https://forums.nesdev.org/viewtopic.php?t=19940) into the game database
+// embedded in the cartridge package, keeping only the games of the given
+// mappers:
+//
+//	go run ./cmd/gamedb -in nes20db.xml -out cartridge/gamedb.xml
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// supportedMappers are the mappers the cartridge package implements.
+const supportedMappers = "0,2,4,18,20,28,30,32,33,48,65,68,94,118,119,180,225,226,227,228"
+
+const header = `<?xml version="1.0" encoding="UTF-8"?>
+<!--
+	Game database used to correct the headers of bad dumps, generated from
+	the NES 2.0 XML database with cmd/gamedb. Each game is identified by the
+	CRC32 and SHA-1 of its PRG-ROM followed by its CHR-ROM:
+
+	<game name="Title" >
+		<rom size="40960" crc32="..." sha1="..." />
+		<pcb mapper="0" submapper="0" mirroring="V" battery="0" board="NES-NROM-256" />
+		<prgram size="8192" />
+		<prgnvram size="8192" />
+		<chrram size="8192" />
+		<chrnvram size="8192" />
+		<console type="0" region="0" />
+	</game>
+
+	mirroring is H, V or 4 for hardwired layouts and 1 when the mapper
+	controls it. Only add entries with checksums taken from verified dumps.
+-->
+`
+
+type game struct {
+	XMLName  xml.Name `xml:"game"`
+	Name     string   `xml:"name,attr"`
+	Rom      rom      `xml:"rom"`
+	Pcb      pcb      `xml:"pcb"`
+	PrgRam   *memory  `xml:"prgram"`
+	PrgNvram *memory  `xml:"prgnvram"`
+	ChrRam   *memory  `xml:"chrram"`
+	ChrNvram *memory  `xml:"chrnvram"`
+	Console  *console `xml:"console"`
+}
+
+type rom struct {
+	Size  int    `xml:"size,attr"`
+	CRC32 string `xml:"crc32,attr"`
+	SHA1  string `xml:"sha1,attr"`
+}
+
+type pcb struct {
+	Mapper    int    `xml:"mapper,attr"`
+	Submapper int    `xml:"submapper,attr"`
+	Mirroring string `xml:"mirroring,attr"`
+	Battery   int    `xml:"battery,attr"`
+	Board     string `xml:"board,attr,omitempty"`
+}
+
+type memory struct {
+	Size int `xml:"size,attr"`
+}
+
+type console struct {
+	Type   int `xml:"type,attr"`
+	Region int `xml:"region,attr"`
+}
+
+func main() {
+	inPath := flag.String("in", "nes20db.xml", "NES 2.0 XML database to convert")
+	outPath := flag.String("out", "gamedb.xml", "game database to write")
+	mapperList := flag.String("mappers", supportedMappers, "comma separated mappers whose games are kept")
+	flag.Parse()
+
+	mappers, err := parseMappers(*mapperList)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	in, err := os.Open(*inPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer in.Close()
+	out, err := os.Create(*outPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	count, err := convert(in, out, mappers)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("wrote %d games to %s\n", count, *outPath)
+}
+
+func parseMappers(list string) (map[int]bool, error) {
+	mappers := make(map[int]bool)
+	for _, value := range strings.Split(list, ",") {
+		mapper, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid mapper %q: %w", value, err)
+		}
+		mappers[mapper] = true
+	}
+	return mappers, nil
+}
+
+// convert writes the games of r using one of mappers to w, returning how
+// many were kept. The NES 2.0 database names each game in the comment
+// before it, with the path of the rom file.
+func convert(r io.Reader, w io.Writer, mappers map[int]bool) (int, error) {
+	if _, err := io.WriteString(w, header+"<nes20db>\n"); err != nil {
+		return 0, err
+	}
+	decoder := xml.NewDecoder(r)
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("\t", "\t")
+	count := 0
+	name := ""
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+		switch token := token.(type) {
+		case xml.Comment:
+			name = gameName(string(token))
+		case xml.StartElement:
+			if token.Name.Local != "game" {
+				continue
+			}
+			var entry game
+			if err := decoder.DecodeElement(&entry, &token); err != nil {
+				return count, err
+			}
+			if !mappers[entry.Pcb.Mapper] || entry.Rom.CRC32 == "" {
+				continue
+			}
+			entry.Name = name
+			if err := encoder.Encode(entry); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return count, err
+	}
+	_, err := io.WriteString(w, "\n</nes20db>\n")
+	return count, err
+}
+
+// gameName turns the rom path in a database comment, like
+// \Licensed\Super Mario Bros. (World).nes, into the game title.
+func gameName(comment string) string {
+	file := path.Base(strings.ReplaceAll(strings.TrimSpace(comment), `\`, "/"))
+	return strings.TrimSuffix(file, path.Ext(file))
+}
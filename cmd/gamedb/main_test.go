@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	db := `<?xml version="1.0" encoding="UTF-8"?>
+<nes20db date="2024-01-01">
+	<!-- \Licensed\Some Game (USA).nes -->
+	<game>
+		<prgrom size="32768" crc32="11111111" sha1="aa" sum16="0000"/>
+		<rom size="32768" crc32="11111111" sha1="aa"/>
+		<pcb mapper="4" submapper="1" mirroring="V" battery="1"/>
+		<prgnvram size="8192"/>
+		<console type="0" region="1"/>
+		<expansion type="1"/>
+	</game>
+	<!-- \Unlicensed\Other Game.nes -->
+	<game>
+		<rom size="16384" crc32="22222222" sha1="bb"/>
+		<pcb mapper="99" submapper="0" mirroring="H" battery="0"/>
+	</game>
+</nes20db>`
+
+	var out bytes.Buffer
+	count, err := convert(strings.NewReader(db), &out, map[int]bool{4: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Contains(t, out.String(), `<game name="Some Game (USA)">`)
+	require.Contains(t, out.String(), `<pcb mapper="4" submapper="1" mirroring="V" battery="1"></pcb>`)
+	require.Contains(t, out.String(), `<prgnvram size="8192"></prgnvram>`)
+	require.NotContains(t, out.String(), "Other Game")
+	require.NotContains(t, out.String(), "expansion")
+}
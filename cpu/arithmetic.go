@@ -8,6 +8,11 @@ func Adc(cpu *CPU, fetchedValue uint16) {
 		carryBit = 1
 	}
 
+	if cpu.decimalMode() {
+		cpu.addDecimal(uint8(fetchedValue), uint8(carryBit))
+		return
+	}
+
 	sum16 := uint16(cpu.A) + fetchedValue + carryBit
 	sum := uint8(sum16)
 
@@ -71,16 +76,70 @@ func Sbc(cpu *CPU, fetchedValue uint16) {
 	s := (sum >> 7) == 1
 	m := (uint8(value) >> 7) == 1
 
-	cpu.SetStatusFlag(StatusFlagNegative, (sum>>7) == 1)
-	cpu.SetStatusFlag(StatusFlagZero, sum == 0)
+	// the flags of a decimal subtraction are the binary ones
+	if cpu.decimalMode() {
+		sum = subtractDecimal(cpu.A, uint8(fetchedValue), uint8(carryBit))
+	}
+
+	cpu.SetStatusFlag(StatusFlagNegative, s)
+	cpu.SetStatusFlag(StatusFlagZero, uint8(sum16) == 0)
 	cpu.SetStatusFlag(StatusFlagCarry, sum16 > 0xFF)
 	cpu.SetStatusFlag(StatusFlagOverflow, (!a && !m && s) || (a && m && !s))
 	cpu.A = sum
 }
 
+// decimalMode reports whether ADC and SBC work on BCD values, which the
+// 2A03 doesn't support.
+func (c *CPU) decimalMode() bool {
+	return c.variant == NMOS6502 && c.GetStatusFlag(StatusFlagDecimal)
+}
+
+// addDecimal adds two BCD values. The zero flag comes from the binary sum,
+// and the negative and overflow flags from the sum before the high digit is
+// adjusted, like the NMOS 6502 does.
+func (c *CPU) addDecimal(value uint8, carry uint8) {
+	a := uint16(c.A)
+	v := uint16(value)
+	sum := (a & 0x0F) + (v & 0x0F) + uint16(carry)
+	if sum > 0x09 {
+		sum += 0x06
+	}
+	if sum > 0x0F {
+		sum = (sum & 0x0F) + (a & 0xF0) + (v & 0xF0) + 0x10
+	} else {
+		sum = (sum & 0x0F) + (a & 0xF0) + (v & 0xF0)
+	}
+
+	c.SetStatusFlag(StatusFlagZero, uint8(a+v+uint16(carry)) == 0)
+	c.SetStatusFlag(StatusFlagNegative, sum&0x80 != 0)
+	c.SetStatusFlag(StatusFlagOverflow, (a^sum)&0x80 != 0 && (a^v)&0x80 == 0)
+	if sum&0x1F0 > 0x90 {
+		sum += 0x60
+	}
+	c.SetStatusFlag(StatusFlagCarry, sum&0xFF0 > 0xF0)
+	c.A = uint8(sum)
+}
+
+// subtractDecimal subtracts two BCD values, where carry is the inverted
+// borrow.
+func subtractDecimal(a uint8, value uint8, carry uint8) uint8 {
+	borrow := 1 - int(carry)
+	lo := int(a&0x0F) - int(value&0x0F) - borrow
+	var diff int
+	if lo&0x10 != 0 {
+		diff = ((lo - 0x06) & 0x0F) | (int(a&0xF0) - int(value&0xF0) - 0x10)
+	} else {
+		diff = (lo & 0x0F) | (int(a&0xF0) - int(value&0xF0))
+	}
+	if diff&0x100 != 0 {
+		diff -= 0x60
+	}
+	return uint8(diff)
+}
+
 func Dcp(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction DCP...")
-	memoryValue := cpu.BusRead(fetchedValue) - 1
+	memoryValue := cpu.readModify(fetchedValue) - 1
 	cpu.BusWrite(fetchedValue, memoryValue)
 	diff := cpu.A - memoryValue
 
@@ -90,25 +149,19 @@ func Dcp(cpu *CPU, fetchedValue uint16) {
 }
 
 func Isc(cpu *CPU, fetchedValue uint16) {
-	memoryValue := cpu.BusRead(fetchedValue) + 1
+	memoryValue := cpu.readModify(fetchedValue) + 1
 	cpu.BusWrite(fetchedValue, memoryValue)
+	Sbc(cpu, uint16(memoryValue))
+}
 
-	var carryBit uint16
-	if cpu.GetStatusFlag(StatusFlagCarry) {
-		carryBit = 1
-	}
+func Axs(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction AXS...")
 
-	value := uint16(memoryValue) ^ 0xFF
-	sum16 := uint16(cpu.A) + value + carryBit
-	sum := uint8(sum16)
+	value := uint8(fetchedValue)
+	andValue := cpu.A & cpu.X
+	cpu.X = andValue - value
 
-	a := (cpu.A >> 7) == 1
-	s := (sum >> 7) == 1
-	m := (uint8(value) >> 7) == 1
-
-	cpu.SetStatusFlag(StatusFlagNegative, (sum>>7) == 1)
-	cpu.SetStatusFlag(StatusFlagZero, sum == 0)
-	cpu.SetStatusFlag(StatusFlagCarry, sum16 > 0xFF)
-	cpu.SetStatusFlag(StatusFlagOverflow, (!a && !m && s) || (a && m && !s))
-	cpu.A = sum
+	cpu.SetStatusFlag(StatusFlagZero, cpu.X == 0)
+	cpu.SetStatusFlag(StatusFlagNegative, (cpu.X>>7) == 1)
+	cpu.SetStatusFlag(StatusFlagCarry, value <= andValue)
 }
@@ -271,3 +271,86 @@ func TestCpyInstruction(t *testing.T) {
 		})
 	}
 }
+
+func TestAxsInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		value     uint16
+		wantValue uint8
+		wantCflag bool
+		wantZflag bool
+		wantNflag bool
+	}{
+		{
+			name:      "test subtraction without borrow",
+			cpu:       cpu.CPU{A: 0b11110000, X: 0b00111100},
+			value:     0x10,
+			wantValue: 0x20,
+			wantCflag: true,
+		},
+		{
+			name:      "test equal values",
+			cpu:       cpu.CPU{A: 0xFF, X: 0x42},
+			value:     0x42,
+			wantValue: 0x00,
+			wantCflag: true,
+			wantZflag: true,
+		},
+		{
+			name:      "test subtraction with borrow",
+			cpu:       cpu.CPU{A: 0x0F, X: 0xFF},
+			value:     0x10,
+			wantValue: 0xFF,
+			wantNflag: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the carry is not used as a borrow input
+			test.cpu.SetStatusFlag(cpu.StatusFlagCarry, false)
+			cpu.Axs(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.X)
+			require.Equal(t, test.wantCflag, test.cpu.GetStatusFlag(cpu.StatusFlagCarry))
+			require.Equal(t, test.wantZflag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+			require.Equal(t, test.wantNflag, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+		})
+	}
+}
+
+func TestDecimalMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		variant   cpu.Variant
+		dispatch  func(*cpu.CPU, uint16)
+		a         uint8
+		value     uint16
+		carry     bool
+		wantValue uint8
+		wantCarry bool
+		wantZero  bool
+	}{
+		{name: "ADC", variant: cpu.NMOS6502, dispatch: cpu.Adc, a: 0x15, value: 0x27, wantValue: 0x42},
+		{name: "ADC with carry in", variant: cpu.NMOS6502, dispatch: cpu.Adc, a: 0x58, value: 0x46, carry: true, wantValue: 0x05, wantCarry: true},
+		// the zero flag comes from the binary sum, $9A
+		{name: "ADC wrapping to zero", variant: cpu.NMOS6502, dispatch: cpu.Adc, a: 0x99, value: 0x01, wantValue: 0x00, wantCarry: true},
+		{name: "SBC", variant: cpu.NMOS6502, dispatch: cpu.Sbc, a: 0x42, value: 0x15, carry: true, wantValue: 0x27, wantCarry: true},
+		{name: "SBC with borrow in", variant: cpu.NMOS6502, dispatch: cpu.Sbc, a: 0x46, value: 0x12, wantValue: 0x33, wantCarry: true},
+		{name: "SBC wrapping below zero", variant: cpu.NMOS6502, dispatch: cpu.Sbc, a: 0x00, value: 0x01, carry: true, wantValue: 0x99},
+		{name: "2A03 ignores the decimal flag", variant: cpu.Ricoh2A03, dispatch: cpu.Adc, a: 0x15, value: 0x27, wantValue: 0x3C},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := cpu.NewCPUVariant(cpu.NewFlatRAM(), test.variant)
+			c.A = test.a
+			c.SetStatusFlag(cpu.StatusFlagDecimal, true)
+			c.SetStatusFlag(cpu.StatusFlagCarry, test.carry)
+			test.dispatch(c, test.value)
+			require.Equal(t, test.wantValue, c.A)
+			require.Equal(t, test.wantCarry, c.GetStatusFlag(cpu.StatusFlagCarry))
+			require.Equal(t, test.wantZero, c.GetStatusFlag(cpu.StatusFlagZero))
+		})
+	}
+}
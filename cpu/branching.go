@@ -4,11 +4,7 @@ func Bcc(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BCC...")
 
 	if !cpu.GetStatusFlag(StatusFlagCarry) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -16,11 +12,7 @@ func Bcs(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BCS...")
 
 	if cpu.GetStatusFlag(StatusFlagCarry) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -28,11 +20,7 @@ func Beq(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BEQ...")
 
 	if cpu.GetStatusFlag(StatusFlagZero) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -40,11 +28,7 @@ func Bmi(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BMI...")
 
 	if cpu.GetStatusFlag(StatusFlagNegative) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -52,11 +36,7 @@ func Bne(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BNE...")
 
 	if !cpu.GetStatusFlag(StatusFlagZero) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -64,11 +44,7 @@ func Bpl(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BPL...")
 
 	if !cpu.GetStatusFlag(StatusFlagNegative) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -76,11 +52,7 @@ func Bvc(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BVC...")
 
 	if !cpu.GetStatusFlag(StatusFlagOverflow) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
 
@@ -88,10 +60,6 @@ func Bvs(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BVS...")
 
 	if cpu.GetStatusFlag(StatusFlagOverflow) {
-		if (cpu.Pc & 0xFF00) != (fetchedValue & 0xFF00) {
-			cpu.ElapseCycle()
-		}
-		cpu.Pc = fetchedValue
-		cpu.ElapseCycle()
+		cpu.branch(fetchedValue)
 	}
 }
@@ -3,6 +3,7 @@ package cpu
 import (
 	"github.com/LucasWillBlumenau/nes/cartridge"
 	"github.com/LucasWillBlumenau/nes/joypad"
+	"github.com/LucasWillBlumenau/nes/poweron"
 	"github.com/LucasWillBlumenau/nes/ppu"
 )
 
@@ -18,6 +19,13 @@ const ppuOAMDMAPortAddr = 0x4014
 const ppuJoypadOnePortAddr = 0x4016
 const ppuJoypadTwoPortAddr = 0x4017
 
+// cartridgeSpaceAddr is where the cartridge address space starts, after
+// the APU and I/O registers
+const cartridgeSpaceAddr = 0x4020
+
+// cartridgeRamAddr is where the cartridge PRG-RAM starts
+const cartridgeRamAddr = 0x6000
+
 type Bus struct {
 	ram       []uint8
 	cartridge *cartridge.Cartridge
@@ -41,7 +49,12 @@ func NewBus(
 	}
 }
 
-func (b *Bus) Write(addr uint16, value uint8) bool {
+// FillRam sets the console RAM to its power on state.
+func (b *Bus) FillRam(state poweron.RamState) {
+	state.Fill(b.ram)
+}
+
+func (b *Bus) Write(addr uint16, value uint8) {
 	if addr < 0x2000 {
 		valueAddress := b.getRamAddress(addr)
 		*valueAddress = value
@@ -63,10 +76,9 @@ func (b *Bus) Write(addr uint16, value uint8) bool {
 		case ppuVRamDataPortAddr:
 			b.ppu.WritePPUDataPort(value)
 		}
-	} else if addr < 0x4200 {
+	} else if addr < cartridgeSpaceAddr {
+		// the sprite DMA at $4014 is part of the CPU
 		switch addr {
-		case ppuOAMDMAPortAddr:
-			return true
 		case ppuJoypadOnePortAddr,
 			ppuJoypadTwoPortAddr:
 			b.joypadOne.Write(value)
@@ -75,7 +87,6 @@ func (b *Bus) Write(addr uint16, value uint8) bool {
 	} else {
 		b.cartridge.WritePrgRom(addr, value)
 	}
-	return false
 }
 
 func (b *Bus) OAMWrite(value uint8) {
@@ -95,7 +106,7 @@ func (b *Bus) Read(addr uint16) uint8 {
 		case ppuVRamDataPortAddr:
 			return b.ppu.ReadVRamDataPort()
 		}
-	} else if addr < 0x4200 {
+	} else if addr < cartridgeSpaceAddr {
 		switch addr {
 		case ppuJoypadOnePortAddr:
 			value := b.joypadOne.Read()
@@ -104,6 +115,18 @@ func (b *Bus) Read(addr uint16) uint8 {
 			value := b.joypadTwo.Read()
 			return value
 		}
+		return 0
+	}
+	return b.cartridge.ReadPrgRom(addr)
+}
+
+// Peek reads addr without side effects. The registers, and the expansion
+// area of the cartridge where some boards map theirs, read as $FF.
+func (b *Bus) Peek(addr uint16) uint8 {
+	if addr < 0x2000 {
+		return *b.getRamAddress(addr)
+	} else if addr < cartridgeRamAddr || b.cartridge == nil {
+		return 0xFF
 	}
 	return b.cartridge.ReadPrgRom(addr)
 }
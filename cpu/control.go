@@ -1,11 +1,10 @@
 package cpu
 
-import "github.com/LucasWillBlumenau/nes/interrupt"
-
 func Brk(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction BRK...")
+	// the byte after BRK was read as the operand and is skipped
 	cpu.Pc++
-	interrupt.InterruptSignal.Send(interrupt.Irq)
+	cpu.enterInterrupt(irqLowByteAddress, true)
 }
 
 func Jmp(cpu *CPU, fetchedValue uint16) {
@@ -18,6 +17,8 @@ func Jsr(cpu *CPU, fetchedValue uint16) {
 
 	programCounter := cpu.Pc - 1
 
+	// the CPU spends a cycle reading the stack before pushing
+	cpu.BusRead(0x0100 | uint16(cpu.Sp))
 	hi := uint8((programCounter & 0xFF00) >> 8)
 	lo := uint8(programCounter & 0x00FF)
 
@@ -30,6 +31,7 @@ func Jsr(cpu *CPU, fetchedValue uint16) {
 func Rti(cpu *CPU, _ uint16) {
 	// fmt.Println("Executing instruction RTI...")
 
+	cpu.BusRead(0x0100 | uint16(cpu.Sp))
 	cpu.P = (cpu.Pop() & 0b11001111) | (cpu.P & 0b00110000)
 
 	lo := cpu.Pop()
@@ -41,9 +43,19 @@ func Rti(cpu *CPU, _ uint16) {
 func Rts(cpu *CPU, _ uint16) {
 	// fmt.Println("Executing instruction RTS...")
 
+	cpu.BusRead(0x0100 | uint16(cpu.Sp))
 	lo := cpu.Pop()
 	hi := cpu.Pop()
 
 	programCounter := (uint16(hi) << 8) | uint16(lo)
+	// the return address points to the last byte of the JSR, which is read
+	// again before moving past it
+	cpu.BusRead(programCounter)
 	cpu.Pc = programCounter + 1
 }
+
+func Jam(cpu *CPU, _ uint16) {
+	// fmt.Println("Executing instruction JAM...")
+	// the program counter already moved past the opcode
+	cpu.halt(cpu.Pc - 1)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/interrupt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +13,186 @@ func TestJmpInstruction(t *testing.T) {
 	cpu.Jmp(&c, 0x9000)
 	require.Equal(t, uint16(0x9000), c.Pc)
 }
+
+func TestJamInstruction(t *testing.T) {
+	for _, opcode := range []uint8{0x02, 0x12, 0x22, 0x32, 0x42, 0x52, 0x62, 0x72, 0x92, 0xB2, 0xD2, 0xF2} {
+		bus := cpu.NewBus(nil, nil, nil, nil)
+		c := cpu.NewCPU(bus)
+		c.Pc = 0x0200
+		bus.Write(0x0200, opcode)
+
+		var haltedAt []uint16
+		c.SetHaltHandler(func(pc uint16) {
+			haltedAt = append(haltedAt, pc)
+		})
+		_, err := c.Run()
+		require.NoError(t, err)
+		require.True(t, c.Halted())
+		require.Equal(t, []uint16{0x0200}, haltedAt)
+
+		pc := c.Pc
+		for range 10 {
+			cycles, err := c.Run()
+			require.NoError(t, err)
+			require.Equal(t, uint16(1), cycles, "the clock keeps running")
+		}
+		require.Equal(t, pc, c.Pc)
+		require.Len(t, haltedAt, 1)
+	}
+}
+
+func TestJamRecoversOnReset(t *testing.T) {
+	memory := cpu.NewFlatRAM()
+	memory.Load(0x0200, []uint8{0x02})
+	memory.Load(0xFFFC, []uint8{0x00, 0x80})
+	memory.Load(0x8000, []uint8{0xEA})
+	c := cpu.NewCPU(memory)
+	c.Pc = 0x0200
+
+	_, err := c.Run()
+	require.NoError(t, err)
+	require.True(t, c.Halted())
+
+	c.Reset()
+	_, err = c.Run()
+	require.NoError(t, err)
+	require.False(t, c.Halted())
+	require.Equal(t, uint16(0x8000), c.Pc)
+
+	_, err = c.Run()
+	require.NoError(t, err)
+	require.Equal(t, uint16(0x8001), c.Pc, "the CPU runs again after the reset")
+}
+
+func TestMaskedIRQ(t *testing.T) {
+	bus := cpu.NewBus(nil, nil, nil, nil)
+	c := cpu.NewCPU(bus)
+	c.Pc = 0x0200
+	c.SetStatusFlag(cpu.StatusFlagInterruptDisable, true)
+	for i := range uint16(4) {
+		bus.Write(0x0200+i, 0xEA)
+	}
+
+	c.Interrupts().SetIRQ(interrupt.SourceMapper, true)
+	for range 4 {
+		_, err := c.Run()
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint16(0x0204), c.Pc, "the IRQ is ignored while interrupts are disabled")
+}
+
+func TestReset(t *testing.T) {
+	memory := cpu.NewFlatRAM()
+	memory.Load(0xFFFC, []uint8{0x00, 0x80})
+	c := cpu.NewCPU(memory)
+
+	c.Reset()
+	cycles, err := c.Run()
+	require.NoError(t, err)
+	require.Equal(t, uint16(7), cycles)
+	require.Equal(t, uint16(0x8000), c.Pc)
+	require.Equal(t, uint8(0xFD), c.Sp)
+	require.True(t, c.GetStatusFlag(cpu.StatusFlagInterruptDisable))
+}
+
+func TestInterruptSequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt func(*cpu.CPU)
+		nmi       bool
+		wantPc    uint16
+		wantBreak bool
+	}{
+		{name: "BRK", wantPc: 0x9000, wantBreak: true},
+		{name: "IRQ", interrupt: func(c *cpu.CPU) { c.Interrupts().SetIRQ(interrupt.SourceMapper, true) }, wantPc: 0x9000},
+		{name: "NMI", interrupt: func(c *cpu.CPU) { c.Interrupts().SetNMI(true) }, wantPc: 0xA000},
+		// the NMI shows up while BRK pushes the return address
+		{name: "NMI hijacking BRK", nmi: true, wantPc: 0xA000, wantBreak: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memory := cpu.NewFlatRAM()
+			// NOP, BRK
+			memory.Load(0x0200, []uint8{0xEA, 0x00})
+			memory.Load(0xFFFA, []uint8{0x00, 0xA0})
+			memory.Load(0xFFFE, []uint8{0x00, 0x90})
+			c := cpu.NewCPU(memory)
+			c.Pc = 0x0200
+			c.SetStatusFlag(cpu.StatusFlagInterruptDisable, false)
+
+			wantReturn := uint16(0x0203)
+			if test.interrupt != nil {
+				// the interrupt is seen during the NOP and runs instead of BRK
+				test.interrupt(c)
+				wantReturn = 0x0201
+			}
+			cycle := 0
+			c.SetCycleHandler(func() {
+				cycle++
+				if test.nmi && cycle == 5 {
+					c.Interrupts().SetNMI(true)
+				}
+			})
+
+			for range 2 {
+				_, err := c.Run()
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.wantPc, c.Pc)
+			require.True(t, c.GetStatusFlag(cpu.StatusFlagInterruptDisable))
+
+			status := memory.Read(0x0100 | uint16(c.Sp+1))
+			require.Equal(t, test.wantBreak, status&0b00010000 != 0)
+			require.NotZero(t, status&0b00100000)
+			returnAddr := uint16(memory.Read(0x0100|uint16(c.Sp+3)))<<8 | uint16(memory.Read(0x0100|uint16(c.Sp+2)))
+			require.Equal(t, wantReturn, returnAddr)
+		})
+	}
+}
+
+func TestInterruptPolling(t *testing.T) {
+	tests := []struct {
+		name    string
+		program []uint8
+		// irqCycle is the cycle the IRQ line goes active on
+		irqCycle int
+		// wantPcs are the values of Pc after each Run
+		wantPcs []uint16
+	}{
+		// the IRQ is polled before CLI clears the flag, so the next
+		// instruction runs first
+		{name: "CLI delay", program: []uint8{0x58, 0xEA, 0xEA}, irqCycle: 1, wantPcs: []uint16{0x0201, 0x0202, 0x9000}},
+		// a taken branch staying in the page doesn't poll on its last
+		// cycle, so the IRQ seen on its second cycle waits for the NOP
+		{name: "branch delay", program: []uint8{0x58, 0xEA, 0xF0, 0x00, 0xEA}, irqCycle: 6, wantPcs: []uint16{0x0201, 0x0202, 0x0204, 0x0205, 0x9000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memory := cpu.NewFlatRAM()
+			memory.Load(0x0200, test.program)
+			memory.Load(0xFFFE, []uint8{0x00, 0x90})
+			c := cpu.NewCPU(memory)
+			c.Pc = 0x0200
+			c.SetStatusFlag(cpu.StatusFlagInterruptDisable, true)
+			c.SetStatusFlag(cpu.StatusFlagZero, true)
+
+			cycle := 0
+			c.SetCycleHandler(func() {
+				cycle++
+				if cycle == test.irqCycle {
+					c.Interrupts().SetIRQ(interrupt.SourceMapper, true)
+				}
+			})
+
+			var pcs []uint16
+			for range test.wantPcs {
+				_, err := c.Run()
+				require.NoError(t, err)
+				pcs = append(pcs, c.Pc)
+			}
+			require.Equal(t, test.wantPcs, pcs)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cpu
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/LucasWillBlumenau/nes/interrupt"
 )
@@ -18,6 +19,13 @@ const irqHighByteAddress = 0xFFFF
 
 type StatusFlag uint8
 
+// statusFlagBreak and statusFlagUnused only exist in the copies of the
+// status pushed to the stack
+const (
+	statusFlagBreak  = 0b00010000
+	statusFlagUnused = 0b00100000
+)
+
 const (
 	StatusFlagCarry            = 0
 	StatusFlagZero             = 1
@@ -28,32 +36,71 @@ const (
 )
 
 type CPU struct {
-	A               uint8
-	X               uint8
-	Y               uint8
-	P               uint8
-	Sp              uint8
-	Pc              uint16
-	elapsedCycles   int64
-	bus             *Bus
-	extraCycles     uint16
-	dmaOccuring     bool
-	dmaPage         uint16
-	dmaFetches      uint16
+	A             uint8
+	X             uint8
+	Y             uint8
+	P             uint8
+	Sp            uint8
+	Pc            uint16
+	elapsedCycles int64
+	variant       Variant
+	memory        Memory
+	ticker        Ticker
+	peeker        Peeker
+	// cycleHandler runs once per CPU cycle, before the bus access of the
+	// cycle, so the devices sharing the clock see the accesses at the
+	// right time
+	cycleHandler func()
+	// halted is set by the JAM opcodes, which lock the CPU until a reset
+	halted       bool
+	haltHandler  func(pc uint16)
+	interrupts   interrupt.Lines
+	resetPending bool
+	// needNmi and runIrq are the interrupts the CPU saw on its last cycle,
+	// and the prev fields the ones it saw on the cycle before. Interrupts
+	// are polled before the last cycle of an instruction, so the prev ones
+	// decide what runs next.
+	needNmi         bool
+	prevNeedNmi     bool
+	runIrq          bool
+	prevRunIrq      bool
+	dma             dmaState
 	lastInstruction *Instruction
+	lastPc          uint16
+	lastOpcode      uint8
 	firstOperand    uint8
 	secondOperand   uint8
+	// operandAddr is the address the last instruction worked on, and
+	// operandPointer the address the indirect modes went through to get
+	// it. operandValue is what operandAddr held before the instruction.
+	operandAddr    uint16
+	operandPointer uint16
+	operandValue   uint8
+}
+
+// NewCPU creates the Ricoh 2A03 CPU of the NES connected to memory.
+func NewCPU(memory Memory) *CPU {
+	return NewCPUVariant(memory, Ricoh2A03)
 }
 
-func NewCPU(bus *Bus) *CPU {
+// NewCPUVariant creates a CPU of the given variant connected to memory.
+// When memory implements Ticker, it is ticked on every cycle, and when it
+// implements Peeker, GetLastInstruction shows the values the instructions
+// worked on.
+func NewCPUVariant(memory Memory, variant Variant) *CPU {
+	ticker, _ := memory.(Ticker)
+	peeker, _ := memory.(Peeker)
 	return &CPU{
-		A:   0,
-		X:   0,
-		Y:   0,
-		P:   0b00100100,
-		Sp:  0xFD,
-		Pc:  0,
-		bus: bus,
+		A:       0,
+		X:       0,
+		Y:       0,
+		P:       0b00100100,
+		Sp:      0xFD,
+		Pc:      0,
+		variant: variant,
+		memory:  memory,
+		ticker:  ticker,
+		peeker:  peeker,
 	}
 }
 
@@ -82,16 +129,66 @@ func (c *CPU) Pop() uint8 {
 	return value
 }
 
+// SetCycleHandler sets the function called on every CPU cycle, which runs
+// the PPU, the APU and the cartridge along with the CPU.
+func (c *CPU) SetCycleHandler(handler func()) {
+	c.cycleHandler = handler
+}
+
+// SetHaltHandler sets the function called when a JAM opcode halts the CPU,
+// with the address of the opcode.
+func (c *CPU) SetHaltHandler(handler func(pc uint16)) {
+	c.haltHandler = handler
+}
+
+// Halted reports whether a JAM opcode locked the CPU. Only a reset brings
+// it back.
+func (c *CPU) Halted() bool {
+	return c.halted
+}
+
+func (c *CPU) halt(pc uint16) {
+	c.halted = true
+	if c.haltHandler != nil {
+		c.haltHandler(pc)
+	}
+}
+
+// Interrupts returns the NMI and IRQ lines of the CPU, which the other
+// devices of the console drive.
+func (c *CPU) Interrupts() *interrupt.Lines {
+	return &c.interrupts
+}
+
+// ElapseCycle spends an internal CPU cycle, without a bus access.
 func (c *CPU) ElapseCycle() {
-	c.extraCycles++
+	c.elapsedCycles++
+	if c.ticker != nil {
+		c.ticker.Tick()
+	}
+	if c.cycleHandler != nil {
+		c.cycleHandler()
+	}
+	c.pollInterrupts()
+}
+
+func (c *CPU) pollInterrupts() {
+	c.prevNeedNmi = c.needNmi
+	if c.interrupts.TakeNMIEdge() {
+		c.needNmi = true
+	}
+	c.prevRunIrq = c.runIrq
+	c.runIrq = c.interrupts.IRQ() && !c.GetStatusFlag(StatusFlagInterruptDisable)
 }
 
 func (c *CPU) ElapsedCycles() int64 {
 	return c.elapsedCycles
 }
 
+// Reset pulls the reset line, which the CPU handles before the next
+// instruction.
 func (c *CPU) Reset() {
-	interrupt.InterruptSignal.Send(interrupt.Reset)
+	c.resetPending = true
 }
 
 func (c *CPU) ResetState() {
@@ -103,107 +200,140 @@ func (c *CPU) ResetState() {
 	c.P = 0b00100100
 	c.Sp = 0xFD
 	c.Pc = uint16(hi)<<8 + uint16(lo)
+	c.halted = false
+	c.needNmi = false
+	c.prevNeedNmi = false
 }
 
+// Run executes the next instruction or interrupt and returns how many cycles
+// it took, including the ones stolen by DMA transfers. The cycle handler already ran for each
+// of them by the time Run returns.
 func (c *CPU) Run() (uint16, error) {
-	c.extraCycles = 0
-	if interrupt, ok := interrupt.InterruptSignal.Read(); ok {
-		c.attendInterrupt(interrupt)
-		c.elapsedCycles += 7
-		return 7, nil
-	}
-
-	if c.dmaOccuring {
-		addr := c.dmaPage | c.dmaFetches
-		value := c.BusRead(addr)
-		c.bus.OAMWrite(value)
-		c.dmaFetches++
-		c.dmaOccuring = c.dmaFetches < 256
-		c.elapsedCycles += 2
-		return 2, nil
-	}
-
-	cyclesTaken, err := c.executeInstruction()
-	if err != nil {
-		return 0, err
+	start := c.elapsedCycles
+	c.lastInstruction = nil
+	switch {
+	case c.resetPending:
+		c.resetPending = false
+		c.attendInterrupt(interrupt.Reset)
+	case c.halted:
+		// the clock keeps running for the rest of the console, but the CPU
+		// ignores everything but a reset
+		c.ElapseCycle()
+	case c.prevNeedNmi:
+		c.needNmi = false
+		c.attendInterrupt(interrupt.NonMaskableInterrupt)
+	case c.prevRunIrq:
+		c.attendInterrupt(interrupt.Irq)
+	default:
+		if err := c.executeInstruction(); err != nil {
+			return 0, err
+		}
 	}
-	c.elapsedCycles += int64(cyclesTaken)
-	return cyclesTaken, err
+	return uint16(c.elapsedCycles - start), nil
 }
 
-func (c *CPU) executeInstruction() (uint16, error) {
+func (c *CPU) executeInstruction() error {
 	c.firstOperand = 0
 	c.secondOperand = 0
+	c.lastPc = c.Pc
 	opcode := c.BusRead(c.Pc)
 	instruction := instructionMap[opcode]
 	if instruction.Dispatch == nil {
-		return 0, fmt.Errorf("%w: invalid opcode %02X", ErrInvalidInstruction, opcode)
+		return fmt.Errorf("%w: invalid opcode %02X", ErrInvalidInstruction, opcode)
 	}
 	c.Pc++
 	c.lastInstruction = &instruction
+	c.lastOpcode = opcode
 
 	value := c.fetchNextValue(instruction.AddressingMode)
+	if c.peeker != nil {
+		c.operandValue = c.peeker.Peek(c.operandAddr)
+	}
 
 	instruction.Dispatch(c, value)
-	return instruction.Cycles + c.extraCycles, nil
+	return nil
 }
 
+// attendInterrupt runs the 7 cycle interrupt sequence: two reads of the
+// next opcode, which is thrown away, three stack accesses and the vector
+// fetch. Reset reads the stack instead of writing to it.
 func (c *CPU) attendInterrupt(interruptValue interrupt.Interrupt) {
+	c.BusRead(c.Pc)
+	c.BusRead(c.Pc)
 	if interruptValue == interrupt.Reset {
+		for i := range uint8(3) {
+			c.BusRead(0x0100 | uint16(c.Sp-i))
+		}
 		c.ResetState()
 		return
 	}
 
-	hi := uint8(c.Pc >> 8)
-	lo := uint8(c.Pc & 0x00FF)
+	vector := uint16(irqLowByteAddress)
+	if interruptValue == interrupt.NonMaskableInterrupt {
+		vector = nmiLowByteAddress
+	}
+	c.enterInterrupt(vector, false)
+}
 
-	c.Push(hi)
-	c.Push(lo)
-	c.Push(c.P)
+// enterInterrupt pushes the return address and the status, with the B flag
+// telling BRK apart from hardware interrupts, and jumps through vector. An
+// NMI showing up before the status is pushed hijacks the sequence, which
+// jumps to the NMI handler instead.
+func (c *CPU) enterInterrupt(vector uint16, isBreak bool) {
+	c.Push(uint8(c.Pc >> 8))
+	c.Push(uint8(c.Pc))
+
+	status := c.P | statusFlagUnused
+	if isBreak {
+		status |= statusFlagBreak
+	} else {
+		status &^= statusFlagBreak
+	}
+	if c.needNmi {
+		c.needNmi = false
+		vector = nmiLowByteAddress
+	}
+	c.Push(status)
 	c.SetStatusFlag(StatusFlagInterruptDisable, true)
 
-	var interruptHandlerLowAddrPar, interruptHandlerHighAddrPart uint8
-	switch interruptValue {
-	case interrupt.NonMaskableInterrupt:
-		interruptHandlerLowAddrPar = c.BusRead(nmiLowByteAddress)
-		interruptHandlerHighAddrPart = c.BusRead(nmiHighByteAddress)
-	case interrupt.Irq:
-		interruptHandlerLowAddrPar = c.BusRead(irqLowByteAddress)
-		interruptHandlerHighAddrPart = c.BusRead(irqHighByteAddress)
+	lo := c.BusRead(vector)
+	hi := c.BusRead(vector + 1)
+	c.Pc = uint16(hi)<<8 | uint16(lo)
+	// the sequence doesn't poll interrupts on its last cycle, so an NMI
+	// seen during it runs after the first instruction of the handler
+	c.prevNeedNmi = false
+}
+
+// branch jumps to the target of a taken branch. The extra cycle of a
+// branch staying in the same page doesn't poll interrupts, so an IRQ
+// showing up then waits for one more instruction.
+func (c *CPU) branch(target uint16) {
+	if c.runIrq && !c.prevRunIrq {
+		c.runIrq = false
+	}
+	c.ElapseCycle()
+	if (c.Pc & 0xFF00) != (target & 0xFF00) {
+		c.ElapseCycle()
 	}
-	c.Pc = uint16(interruptHandlerHighAddrPart)<<8 + uint16(interruptHandlerLowAddrPar)
+	c.Pc = target
 }
 
 func (c *CPU) fetchNextValue(addressingMode AddressingMode) uint16 {
 	switch addressingMode {
 	case Implied, Accumulator:
+		// the byte after the opcode is read and thrown away
+		c.BusRead(c.Pc)
 		return 0
 	case Immediate:
 		return uint16(c.getImmediateValue())
 	case XIndexedAbsoluteValue:
-		addr := c.getAbsoluteAddress()
-		addrPage := addr & 0xFF00
-		addr = addr + uint16(c.X)
-		if addrPage != (addr & 0xFF00) {
-			c.extraCycles++
-		}
-		return uint16(c.BusRead(addr))
+		return uint16(c.readIndexed(c.getAbsoluteAddress(), c.X))
 	case XIndexedAbsolute:
-		addr := c.getAbsoluteAddress()
-		addr = addr + uint16(c.X)
-		return addr
+		return c.indexForWrite(c.getAbsoluteAddress(), c.X)
 	case YIndexedAbsoluteValue:
-		addr := c.getAbsoluteAddress()
-		addrPage := addr & 0xFF00
-		addr = addr + uint16(c.Y)
-		if addrPage != (addr & 0xFF00) {
-			c.extraCycles++
-		}
-		return uint16(c.BusRead(addr))
+		return uint16(c.readIndexed(c.getAbsoluteAddress(), c.Y))
 	case YIndexedAbsolute:
-		addr := c.getAbsoluteAddress()
-		addr = addr + uint16(c.Y)
-		return addr
+		return c.indexForWrite(c.getAbsoluteAddress(), c.Y)
 	case AbsoluteIndirect:
 		loAddr := c.getAbsoluteAddress()
 		hiAddr := loAddr + 1
@@ -212,27 +342,30 @@ func (c *CPU) fetchNextValue(addressingMode AddressingMode) uint16 {
 		}
 		lo := c.BusRead(loAddr)
 		hi := c.BusRead(hiAddr)
-		return uint16(hi)<<8 + uint16(lo)
+		c.operandAddr = uint16(hi)<<8 + uint16(lo)
+		return c.operandAddr
 	case AbsoluteValue:
 		addr := c.getAbsoluteAddress()
-		return uint16(c.BusRead(addr))
+		return uint16(c.readOperand(addr))
 	case Absolute:
-		return c.getAbsoluteAddress()
+		c.operandAddr = c.getAbsoluteAddress()
+		return c.operandAddr
 	case ZeroPageValue:
 		addr := uint16(c.getImmediateValue())
-		return uint16(c.BusRead(addr))
+		return uint16(c.readOperand(addr))
 	case ZeroPage:
-		return uint16(c.getImmediateValue())
+		c.operandAddr = uint16(c.getImmediateValue())
+		return c.operandAddr
 	case XIndexedZeroPageValue:
-		addr := uint16(c.getImmediateValue() + c.X)
-		return uint16(c.BusRead(addr))
+		return uint16(c.readOperand(c.indexZeroPage(c.X)))
 	case XIndexedZeroPage:
-		return uint16(c.getImmediateValue() + c.X)
+		c.operandAddr = c.indexZeroPage(c.X)
+		return c.operandAddr
 	case YIndexedZeroPageValue:
-		addr := uint16(c.getImmediateValue() + c.Y)
-		return uint16(c.BusRead(addr))
+		return uint16(c.readOperand(c.indexZeroPage(c.Y)))
 	case YIndexedZeroPage:
-		return uint16(c.getImmediateValue() + c.Y)
+		c.operandAddr = c.indexZeroPage(c.Y)
+		return c.operandAddr
 	case Relative:
 		offset := c.BusRead(c.Pc)
 		c.firstOperand = uint8(offset)
@@ -240,39 +373,67 @@ func (c *CPU) fetchNextValue(addressingMode AddressingMode) uint16 {
 		positivePart := uint16(offset & 0b01111111)
 		negativePart := uint16(offset & 0b10000000)
 		nextAddr := c.Pc + positivePart - negativePart
+		c.operandAddr = nextAddr
 		return nextAddr
 	case XIndexedZeroPageIndirectValue:
-		indirectAddr := c.getImmediateValue() + c.X
-		lo := c.BusRead(uint16(indirectAddr))
-		hi := c.BusRead(uint16(indirectAddr + 1))
-		addr := uint16(hi)<<8 | uint16(lo)
-		return uint16(c.BusRead(addr))
+		c.operandPointer = c.indexZeroPage(c.X)
+		return uint16(c.readOperand(c.readZeroPagePointer(uint8(c.operandPointer))))
 	case XIndexedZeroPageIndirect:
-		indirectAddr := c.getImmediateValue() + c.X
-		lo := c.BusRead(uint16(indirectAddr))
-		hi := c.BusRead(uint16(indirectAddr + 1))
-		return uint16(hi)<<8 | uint16(lo)
+		c.operandPointer = c.indexZeroPage(c.X)
+		c.operandAddr = c.readZeroPagePointer(uint8(c.operandPointer))
+		return c.operandAddr
 	case ZeroPageIndirectYIndexedValue:
-		value := c.getImmediateValue()
-		lo := c.BusRead(uint16(value))
-		hi := c.BusRead(uint16(value + 1))
-		baseAddr := (uint16(hi) << 8) + uint16(lo)
-		baseAddrPage := baseAddr & 0xFF00
-		addr := (uint16(hi) << 8) + uint16(lo) + uint16(c.Y)
-		if (addr & 0xFF00) != baseAddrPage {
-			c.extraCycles++
-		}
-		return uint16(c.BusRead(addr))
+		c.operandPointer = c.readZeroPagePointer(c.getImmediateValue())
+		return uint16(c.readIndexed(c.operandPointer, c.Y))
 	case ZeroPageIndirectYIndexed:
-		value := c.getImmediateValue()
-		lo := c.BusRead(uint16(value))
-		hi := c.BusRead(uint16(value + 1))
-		addr := (uint16(hi) << 8) + uint16(lo) + uint16(c.Y)
-		return addr
+		c.operandPointer = c.readZeroPagePointer(c.getImmediateValue())
+		c.operandAddr = c.indexForWrite(c.operandPointer, c.Y)
+		return c.operandAddr
 	}
 	panic("should never get here")
 }
 
+// indexZeroPage fetches a zero page address and adds index to it, which
+// takes a cycle where the unindexed address is read.
+func (c *CPU) indexZeroPage(index uint8) uint16 {
+	addr := c.getImmediateValue()
+	c.BusRead(uint16(addr))
+	return uint16(addr + index)
+}
+
+func (c *CPU) readZeroPagePointer(addr uint8) uint16 {
+	lo := c.BusRead(uint16(addr))
+	hi := c.BusRead(uint16(addr + 1))
+	return uint16(hi)<<8 | uint16(lo)
+}
+
+// readIndexed reads baseAddr+index. The CPU adds the index to the low byte
+// first and reads from there, so crossing a page costs a read of the
+// address without the carry before the right one.
+func (c *CPU) readIndexed(baseAddr uint16, index uint8) uint8 {
+	addr := baseAddr + uint16(index)
+	if addr&0xFF00 != baseAddr&0xFF00 {
+		c.BusRead(addr - 0x0100)
+	}
+	return c.readOperand(addr)
+}
+
+// readOperand reads the value an instruction works on from addr.
+func (c *CPU) readOperand(addr uint16) uint8 {
+	c.operandAddr = addr
+	return c.BusRead(addr)
+}
+
+// indexForWrite returns baseAddr+index for instructions writing to it,
+// which always spend the read of the address without the carry, as they
+// can't undo a write to the wrong page.
+func (c *CPU) indexForWrite(baseAddr uint16, index uint8) uint16 {
+	addr := baseAddr + uint16(index)
+	c.BusRead(baseAddr&0xFF00 | addr&0x00FF)
+	c.operandAddr = addr
+	return addr
+}
+
 func (c *CPU) getImmediateValue() uint8 {
 	value := c.BusRead(c.Pc)
 	c.Pc++
@@ -290,68 +451,106 @@ func (c *CPU) getAbsoluteAddress() uint16 {
 	return uint16(hi)<<8 + uint16(lo)
 }
 
+// BusRead reads from the bus, taking a cycle. The pending DMA transfers
+// run before the read.
 func (c *CPU) BusRead(addr uint16) uint8 {
-	return c.bus.Read(addr)
+	c.runPendingDma(addr)
+	c.ElapseCycle()
+	return c.memory.Read(addr)
 }
 
+// BusWrite writes to the bus, taking a cycle.
 func (c *CPU) BusWrite(addr uint16, value uint8) {
-	dmaRequested := c.bus.Write(addr, value)
-	if dmaRequested {
-		c.dmaOccuring = true
-		c.dmaFetches = 0
-		c.dmaPage = uint16(value) << 8
+	c.ElapseCycle()
+	c.memory.Write(addr, value)
+	if c.variant == Ricoh2A03 && addr == ppuOAMDMAPortAddr {
+		c.startSpriteDma(value)
 	}
 }
 
+// readModify reads the operand of a read-modify-write instruction. The CPU
+// writes the value back unchanged while it computes the result, which is
+// written on the next cycle, so devices see two writes.
+func (c *CPU) readModify(addr uint16) uint8 {
+	value := c.BusRead(addr)
+	c.BusWrite(addr, value)
+	return value
+}
+
+// State formats the registers like the logs of the Nintendulator emulator.
 func (c *CPU) State() string {
 	return fmt.Sprintf(
-		"A: %02x, X: %02x, Y: %02x, P: %02x, SP: %02x, PC: %04x",
+		"A:%02X X:%02X Y:%02X P:%02X SP:%02X",
 		c.A,
 		c.X,
 		c.Y,
 		c.P,
 		c.Sp,
-		c.Pc,
 	)
 }
 
+// GetLastInstruction formats the address, bytes and disassembly of the
+// last instruction Run executed like the logs of the Nintendulator
+// emulator, or returns an empty string when Run didn't execute one.
 func (c *CPU) GetLastInstruction() string {
 	if c.lastInstruction == nil {
 		return ""
 	}
-	return c.formatInstrucitionAsAsm(c.lastInstruction)
+	bytes := fmt.Sprintf("%02X", c.lastOpcode)
+	switch c.lastInstruction.AddressingMode {
+	case Implied, Accumulator:
+	case Absolute, AbsoluteValue, AbsoluteIndirect,
+		XIndexedAbsolute, XIndexedAbsoluteValue,
+		YIndexedAbsolute, YIndexedAbsoluteValue:
+		bytes += fmt.Sprintf(" %02X %02X", c.firstOperand, c.secondOperand)
+	default:
+		bytes += fmt.Sprintf(" %02X", c.firstOperand)
+	}
+
+	asm := c.formatInstrucitionAsAsm(c.lastInstruction)
+	// the unofficial opcodes are named with a leading *, which takes the
+	// place of the space
+	if !strings.HasPrefix(asm, "*") {
+		asm = " " + asm
+	}
+	return fmt.Sprintf("%04X  %-8s %s", c.lastPc, bytes, asm)
 }
 
 func (c *CPU) formatInstrucitionAsAsm(instruction *Instruction) string {
+	name := instruction.Name
+	absolute := uint16(c.secondOperand)<<8 | uint16(c.firstOperand)
 	var asm string
 	switch instruction.AddressingMode {
 	case ZeroPage, ZeroPageValue:
-		asm = fmt.Sprintf("%s $%02X", instruction.Name, c.firstOperand)
+		asm = fmt.Sprintf("%s $%02X = %02X", name, c.firstOperand, c.operandValue)
 	case XIndexedZeroPage, XIndexedZeroPageValue:
-		asm = fmt.Sprintf("%s $%02X, X", instruction.Name, c.firstOperand)
+		asm = fmt.Sprintf("%s $%02X,X @ %02X = %02X", name, c.firstOperand, c.operandAddr, c.operandValue)
 	case YIndexedZeroPage, YIndexedZeroPageValue:
-		asm = fmt.Sprintf("%s $%02X, Y", instruction.Name, c.firstOperand)
+		asm = fmt.Sprintf("%s $%02X,Y @ %02X = %02X", name, c.firstOperand, c.operandAddr, c.operandValue)
 	case Absolute, AbsoluteValue:
-		asm = fmt.Sprintf("%s $%02X%02X", instruction.Name, c.secondOperand, c.firstOperand)
+		if name == "JMP" || name == "JSR" {
+			asm = fmt.Sprintf("%s $%04X", name, absolute)
+		} else {
+			asm = fmt.Sprintf("%s $%04X = %02X", name, absolute, c.operandValue)
+		}
 	case Relative:
-		addr := int(c.Pc-2) + int(c.firstOperand) + 2
-		asm = fmt.Sprintf("%s $%04X", instruction.Name, addr)
+		asm = fmt.Sprintf("%s $%04X", name, c.operandAddr)
 	case XIndexedAbsolute, XIndexedAbsoluteValue:
-		asm = fmt.Sprintf("%s $%02X%02X, X", instruction.Name, c.secondOperand, c.firstOperand)
+		asm = fmt.Sprintf("%s $%04X,X @ %04X = %02X", name, absolute, c.operandAddr, c.operandValue)
 	case YIndexedAbsolute, YIndexedAbsoluteValue:
-		asm = fmt.Sprintf("%s $%02X%02X, Y", instruction.Name, c.secondOperand, c.firstOperand)
+		asm = fmt.Sprintf("%s $%04X,Y @ %04X = %02X", name, absolute, c.operandAddr, c.operandValue)
 	case AbsoluteIndirect:
-		asm = fmt.Sprintf("%s ($%02X%02X)", instruction.Name, c.secondOperand, c.firstOperand)
+		asm = fmt.Sprintf("%s ($%04X) = %04X", name, absolute, c.operandAddr)
 	case Implied:
-		asm = instruction.Name
+		asm = name
 	case Accumulator:
-		asm = fmt.Sprintf("%s A", instruction.Name)
+		asm = fmt.Sprintf("%s A", name)
 	case Immediate:
-		asm = fmt.Sprintf("%s #$%02X", instruction.Name, c.firstOperand)
+		asm = fmt.Sprintf("%s #$%02X", name, c.firstOperand)
 	case XIndexedZeroPageIndirect, XIndexedZeroPageIndirectValue:
-		asm = fmt.Sprintf("%s ($%02X%02X, X)", instruction.Name, c.secondOperand, c.firstOperand)
+		asm = fmt.Sprintf("%s ($%02X,X) @ %02X = %04X = %02X", name, c.firstOperand, uint8(c.operandPointer), c.operandAddr, c.operandValue)
 	case ZeroPageIndirectYIndexed, ZeroPageIndirectYIndexedValue:
-		asm = fmt.Sprintf("%s ($%02X), Y", instruction.Name, c.firstOperand)
+		asm = fmt.Sprintf("%s ($%02X),Y = %04X @ %04X = %02X", name, c.firstOperand, c.operandPointer, c.operandAddr, c.operandValue)
 	default:
 		panic("invalid addressing mode")
 	}
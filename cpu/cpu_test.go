@@ -0,0 +1,101 @@
+package cpu_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstructionCycles(t *testing.T) {
+	tests := []struct {
+		name       string
+		program    []uint8
+		x          uint8
+		y          uint8
+		wantCycles uint16
+	}{
+		{name: "NOP", program: []uint8{0xEA}, wantCycles: 2},
+		{name: "LDA immediate", program: []uint8{0xA9, 0x01}, wantCycles: 2},
+		{name: "LDA zero page", program: []uint8{0xA5, 0x10}, wantCycles: 3},
+		{name: "LDA zero page X", program: []uint8{0xB5, 0x10}, x: 1, wantCycles: 4},
+		{name: "LDA absolute X", program: []uint8{0xBD, 0x00, 0x03}, x: 1, wantCycles: 4},
+		{name: "LDA absolute X crossing a page", program: []uint8{0xBD, 0xFF, 0x03}, x: 1, wantCycles: 5},
+		{name: "LDA indirect X", program: []uint8{0xA1, 0x10}, x: 1, wantCycles: 6},
+		{name: "LDA indirect Y", program: []uint8{0xB1, 0x10}, wantCycles: 5},
+		{name: "LDA indirect Y crossing a page", program: []uint8{0xB1, 0x10}, y: 0xFF, wantCycles: 6},
+		{name: "STA absolute X", program: []uint8{0x9D, 0x00, 0x03}, wantCycles: 5},
+		{name: "STA indirect Y", program: []uint8{0x91, 0x10}, wantCycles: 6},
+		{name: "ASL accumulator", program: []uint8{0x0A}, wantCycles: 2},
+		{name: "ASL absolute", program: []uint8{0x0E, 0x00, 0x03}, wantCycles: 6},
+		{name: "ASL absolute X", program: []uint8{0x1E, 0x00, 0x03}, wantCycles: 7},
+		{name: "INC zero page", program: []uint8{0xE6, 0x10}, wantCycles: 5},
+		{name: "DCP indirect Y", program: []uint8{0xD3, 0x10}, wantCycles: 8},
+		{name: "NOP absolute", program: []uint8{0x0C, 0x00, 0x03}, wantCycles: 4},
+		{name: "NOP zero page X", program: []uint8{0x14, 0x10}, wantCycles: 4},
+		{name: "LAS absolute Y", program: []uint8{0xBB, 0x00, 0x03}, wantCycles: 4},
+		{name: "SHA indirect Y", program: []uint8{0x93, 0x10}, wantCycles: 6},
+		{name: "TAS absolute Y", program: []uint8{0x9B, 0x00, 0x03}, wantCycles: 5},
+		{name: "PHA", program: []uint8{0x48}, wantCycles: 3},
+		{name: "PLA", program: []uint8{0x68}, wantCycles: 4},
+		{name: "JSR", program: []uint8{0x20, 0x00, 0x03}, wantCycles: 6},
+		{name: "RTS", program: []uint8{0x60}, wantCycles: 6},
+		{name: "RTI", program: []uint8{0x40}, wantCycles: 6},
+		{name: "JMP indirect", program: []uint8{0x6C, 0x00, 0x03}, wantCycles: 5},
+		{name: "branch not taken", program: []uint8{0xD0, 0x10}, wantCycles: 2},
+		{name: "branch taken", program: []uint8{0xF0, 0x05}, wantCycles: 3},
+		{name: "branch taken crossing a page", program: []uint8{0xF0, 0x7F}, wantCycles: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := cpu.NewBus(nil, nil, nil, nil)
+			c := cpu.NewCPU(bus)
+			// the program runs from the end of a page, so long branches
+			// cross to the next one
+			c.Pc = 0x02F0
+			c.X = test.x
+			c.Y = test.y
+			c.SetStatusFlag(cpu.StatusFlagZero, true)
+			for i, value := range test.program {
+				bus.Write(c.Pc+uint16(i), value)
+			}
+			// pointer used by the indirect addressing modes
+			bus.Write(0x0010, 0x01)
+			bus.Write(0x0011, 0x03)
+
+			handlerCalls := 0
+			c.SetCycleHandler(func() {
+				handlerCalls++
+			})
+			cycles, err := c.Run()
+			require.NoError(t, err)
+			require.Equal(t, test.wantCycles, cycles)
+			require.Equal(t, int(cycles), handlerCalls)
+			require.Equal(t, int64(cycles), c.ElapsedCycles())
+		})
+	}
+}
+
+type tickingMemory struct {
+	*cpu.FlatRAM
+	ticks int
+}
+
+func (m *tickingMemory) Tick() {
+	m.ticks++
+}
+
+func TestMemoryTick(t *testing.T) {
+	memory := &tickingMemory{FlatRAM: cpu.NewFlatRAM()}
+	// LDA $03FF,X crossing a page
+	memory.Load(0x0200, []uint8{0xBD, 0xFF, 0x03})
+	c := cpu.NewCPU(memory)
+	c.Pc = 0x0200
+	c.X = 1
+
+	cycles, err := c.Run()
+	require.NoError(t, err)
+	require.Equal(t, uint16(5), cycles)
+	require.Equal(t, 5, memory.ticks)
+}
@@ -0,0 +1,103 @@
+package cpu
+
+// spriteDmaCycles is how many cycles the sprite DMA spends copying a page
+// to the OAM, alternating reads and writes.
+const spriteDmaCycles = 512
+
+// dmaState holds the DMA transfers waiting for the CPU to be halted. The
+// CPU can only be halted on a read cycle, so the transfers start on its
+// next read.
+type dmaState struct {
+	needHalt      bool
+	needDummyRead bool
+	sprite        bool
+	spritePage    uint16
+	dmc           bool
+	dmcAddr       uint16
+	dmcDone       func(value uint8)
+}
+
+// StartDMCTransfer fetches a DMC sample byte from addr. The transfer
+// steals 3 or 4 cycles from the CPU on its next read, or fewer when it
+// overlaps a sprite DMA, and done is called with the byte it read.
+func (c *CPU) StartDMCTransfer(addr uint16, done func(value uint8)) {
+	c.dma.dmc = true
+	c.dma.dmcAddr = addr
+	c.dma.dmcDone = done
+	c.dma.needHalt = true
+	c.dma.needDummyRead = true
+}
+
+func (c *CPU) startSpriteDma(page uint8) {
+	c.dma.sprite = true
+	c.dma.spritePage = uint16(page) << 8
+	c.dma.needHalt = true
+}
+
+// runPendingDma runs the DMA transfers before the CPU reads from addr. The
+// transfers read on get cycles and write on put cycles, so the sprite DMA
+// takes 513 cycles when the halt cycle is a put cycle and 514 when it needs
+// one more cycle to align. While halted, the CPU repeats its read on each
+// cycle the transfers don't use the bus, which reading $2007 notices. The
+// joypads are clocked when their /OE line falls, so back to back reads of a
+// port count as one, but the first read after a transfer used the bus
+// clocks them again and drops a bit, the DPCM controller bug.
+func (c *CPU) runPendingDma(addr uint16) {
+	if !c.dma.needHalt {
+		return
+	}
+	isJoypadPort := addr == ppuJoypadOnePortAddr || addr == ppuJoypadTwoPortAddr
+	// joypadSelected tracks /OE, which stays low while the halted CPU keeps
+	// reading the port
+	joypadSelected := false
+	repeatRead := func() {
+		if !isJoypadPort || !joypadSelected {
+			c.memory.Read(addr)
+		}
+		joypadSelected = isJoypadPort
+	}
+
+	c.ElapseCycle()
+	repeatRead()
+	c.dma.needHalt = false
+
+	var value uint8
+	spriteCycle := 0
+	for c.dma.dmc || c.dma.sprite {
+		isGetCycle := c.elapsedCycles%2 == 0
+		switch {
+		case isGetCycle && c.dma.dmc && !c.dma.needHalt && !c.dma.needDummyRead:
+			c.dmaCycle()
+			c.dma.dmc = false
+			joypadSelected = false
+			c.dma.dmcDone(c.memory.Read(c.dma.dmcAddr))
+		case isGetCycle && c.dma.sprite:
+			c.dmaCycle()
+			joypadSelected = false
+			value = c.memory.Read(c.dma.spritePage | uint16(spriteCycle/2))
+			spriteCycle++
+		case !isGetCycle && c.dma.sprite && spriteCycle%2 == 1:
+			c.dmaCycle()
+			c.memory.Write(ppuOAMDataPortAddr, value)
+			spriteCycle++
+			c.dma.sprite = spriteCycle < spriteDmaCycles
+		default:
+			// aligns the transfers with the get cycles, or waits for the
+			// dummy read of the DMC transfer
+			c.dmaCycle()
+			repeatRead()
+		}
+	}
+}
+
+// dmaCycle spends a cycle of the DMA transfers. A DMC transfer started in
+// the middle of a sprite DMA counts any of its cycles as its halt and dummy
+// read cycles.
+func (c *CPU) dmaCycle() {
+	if c.dma.needHalt {
+		c.dma.needHalt = false
+	} else if c.dma.needDummyRead {
+		c.dma.needDummyRead = false
+	}
+	c.ElapseCycle()
+}
@@ -0,0 +1,118 @@
+package cpu_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/joypad"
+	"github.com/LucasWillBlumenau/nes/ppu"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpriteDma(t *testing.T) {
+	tests := []struct {
+		name       string
+		program    []uint8
+		wantCycles uint16
+	}{
+		// LDA #$03, STA $4014, NOP
+		{name: "halted on a get cycle", program: []uint8{0xA9, 0x03, 0x8D, 0x14, 0x40, 0xEA}, wantCycles: 2 + 514},
+		// LDA $10, STA $4014, NOP
+		{name: "halted on a put cycle", program: []uint8{0xA5, 0x10, 0x8D, 0x14, 0x40, 0xEA}, wantCycles: 2 + 513},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := ppu.NewPPU(ppu.NewPPUBus(nil), nil, 1)
+			bus := cpu.NewBus(p, nil, nil, nil)
+			c := cpu.NewCPU(bus)
+			c.Pc = 0x0200
+			for i, value := range test.program {
+				bus.Write(c.Pc+uint16(i), value)
+			}
+			bus.Write(0x0010, 0x03)
+			for i := range uint16(256) {
+				bus.Write(0x0300+i, uint8(i)^0xFF)
+			}
+
+			for range 2 {
+				_, err := c.Run()
+				require.NoError(t, err)
+			}
+			cycles, err := c.Run()
+			require.NoError(t, err)
+			require.Equal(t, test.wantCycles, cycles)
+			require.Equal(t, uint16(0x0206), c.Pc)
+
+			for _, addr := range []uint8{0x00, 0x01, 0x80, 0xFF} {
+				bus.Write(0x2003, addr)
+				require.Equal(t, addr^0xFF, bus.Read(0x2004))
+			}
+		})
+	}
+}
+
+func TestDMCDma(t *testing.T) {
+	tests := []struct {
+		name       string
+		program    []uint8
+		wantCycles uint16
+	}{
+		// NOP, NOP
+		{name: "halted on a get cycle", program: []uint8{0xEA, 0xEA}, wantCycles: 2 + 3},
+		// LDA $10, NOP
+		{name: "halted on a put cycle", program: []uint8{0xA5, 0x10, 0xEA}, wantCycles: 2 + 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := cpu.NewBus(nil, nil, nil, nil)
+			c := cpu.NewCPU(bus)
+			c.Pc = 0x0200
+			for i, value := range test.program {
+				bus.Write(c.Pc+uint16(i), value)
+			}
+			bus.Write(0x0180, 0x42)
+
+			_, err := c.Run()
+			require.NoError(t, err)
+
+			var samples []uint8
+			c.StartDMCTransfer(0x0180, func(value uint8) {
+				samples = append(samples, value)
+			})
+			cycles, err := c.Run()
+			require.NoError(t, err)
+			require.Equal(t, test.wantCycles, cycles)
+			require.Equal(t, []uint8{0x42}, samples)
+		})
+	}
+}
+
+func TestDMCDmaDuringJoypadRead(t *testing.T) {
+	j := joypad.New()
+	j.SetControl(joypad.ButtonB, true)
+	j.Write(1)
+	j.Write(0)
+
+	bus := cpu.NewBus(nil, nil, j, nil)
+	c := cpu.NewCPU(bus)
+	c.Pc = 0x0200
+	// LDA $4016
+	for i, value := range []uint8{0xAD, 0x16, 0x40} {
+		bus.Write(c.Pc+uint16(i), value)
+	}
+
+	cycle := 0
+	c.SetCycleHandler(func() {
+		cycle++
+		// the DMC transfer halts the CPU on the read of $4016
+		if cycle == 3 {
+			c.StartDMCTransfer(0x0180, func(uint8) {})
+		}
+	})
+	cycles, err := c.Run()
+	require.NoError(t, err)
+	require.Greater(t, cycles, uint16(4))
+	require.Equal(t, uint8(1), c.A, "the read after the DMC fetch clocks the joypad again, dropping the A bit")
+}
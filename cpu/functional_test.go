@@ -0,0 +1,99 @@
+package cpu_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/interrupt"
+	"github.com/stretchr/testify/require"
+)
+
+// The test suites by Klaus Dormann (https://github.com/Klaus2m5/6502_65C02_functional_tests)
+// aren't distributed with the emulator. To run them, assemble the binaries
+// and copy them to the testdata folder. The interrupt test must be
+// assembled with I_drive = 0, so the bits of its feedback port are active
+// high.
+const (
+	functionalTestFile = "6502_functional_test.bin"
+	interruptTestFile  = "6502_interrupt_test.bin"
+)
+
+const (
+	suiteStartAddr = 0x0400
+	// suiteTestCaseAddr is where the suites store the number of the test
+	// case running
+	suiteTestCaseAddr = 0x0200
+	// suiteFeedbackAddr is the port the interrupt test drives the IRQ,
+	// bit 0, and the NMI, bit 1, through
+	suiteFeedbackAddr = 0xBFFC
+	suiteMaxCycles    = 200_000_000
+)
+
+// feedbackMemory is a flat memory with the interrupt feedback port of the
+// interrupt test.
+type feedbackMemory struct {
+	*cpu.FlatRAM
+	interrupts *interrupt.Lines
+}
+
+func (m *feedbackMemory) Write(addr uint16, value uint8) {
+	m.FlatRAM.Write(addr, value)
+	if addr == suiteFeedbackAddr && m.interrupts != nil {
+		m.interrupts.SetIRQ(interrupt.SourceMapper, value&0b01 != 0)
+		m.interrupts.SetNMI(value&0b10 != 0)
+	}
+}
+
+func TestFunctionalSuites(t *testing.T) {
+	tests := []struct {
+		file        string
+		successAddr uint16
+	}{
+		{file: functionalTestFile, successAddr: 0x3469},
+		{file: interruptTestFile, successAddr: 0x06F5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			if testing.Short() {
+				t.Skip("the suite takes a few seconds to run")
+			}
+			image, err := os.ReadFile(filepath.Join("testdata", test.file))
+			if errors.Is(err, os.ErrNotExist) {
+				t.Skipf("testdata/%s is missing", test.file)
+			}
+			require.NoError(t, err)
+
+			memory := &feedbackMemory{FlatRAM: cpu.NewFlatRAM()}
+			memory.Load(0x0000, image)
+			c := cpu.NewCPUVariant(memory, cpu.NMOS6502)
+			memory.interrupts = c.Interrupts()
+			memory.Write(suiteFeedbackAddr, 0)
+			c.Pc = suiteStartAddr
+
+			for c.ElapsedCycles() < suiteMaxCycles {
+				pc := c.Pc
+				_, err := c.Run()
+				require.NoError(t, err)
+				if c.Pc != pc {
+					continue
+				}
+				// the suites trap failures and the success in jumps or
+				// branches to themselves
+				require.Equal(
+					t,
+					test.successAddr,
+					pc,
+					"trapped at $%04X in test case $%02X",
+					pc,
+					memory.Read(suiteTestCaseAddr),
+				)
+				return
+			}
+			t.Fatalf("no trap after %d cycles, running test case $%02X", suiteMaxCycles, memory.Read(suiteTestCaseAddr))
+		})
+	}
+}
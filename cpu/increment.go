@@ -3,7 +3,7 @@ package cpu
 func Dec(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction DEC...")
 
-	currentValue := cpu.BusRead(fetchedValue)
+	currentValue := cpu.readModify(fetchedValue)
 	result := currentValue - 1
 
 	cpu.SetStatusFlag(StatusFlagNegative, (result>>7) == 1)
@@ -31,7 +31,7 @@ func Dey(cpu *CPU, _ uint16) {
 func Inc(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction INC...")
 
-	currentValue := cpu.BusRead(fetchedValue)
+	currentValue := cpu.readModify(fetchedValue)
 	result := currentValue + 1
 
 	cpu.SetStatusFlag(StatusFlagNegative, (result>>7) == 1)
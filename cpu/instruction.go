@@ -310,7 +310,7 @@ var instructionMap = [256]Instruction{
 	// INY
 	0xC8: {Name: "INY", Dispatch: Iny, AddressingMode: Implied, Cycles: 2},
 	// BRK
-	0x00: {Name: "BRK", Dispatch: Brk, AddressingMode: Implied, Cycles: 0},
+	0x00: {Name: "BRK", Dispatch: Brk, AddressingMode: Implied, Cycles: 7},
 	// JMP
 	0x4C: {Name: "JMP", Dispatch: Jmp, AddressingMode: Absolute, Cycles: 3},
 	0x6C: {Name: "JMP", Dispatch: Jmp, AddressingMode: AbsoluteIndirect, Cycles: 5},
@@ -350,6 +350,41 @@ var instructionMap = [256]Instruction{
 	0xF8: {Name: "SED", Dispatch: Sed, AddressingMode: Implied, Cycles: 2},
 	// SEI
 	0x78: {Name: "SEI", Dispatch: Sei, AddressingMode: Implied, Cycles: 2},
+	// ANC
+	0x0B: {Name: "*ANC", Dispatch: Anc, AddressingMode: Immediate, Cycles: 2},
+	0x2B: {Name: "*ANC", Dispatch: Anc, AddressingMode: Immediate, Cycles: 2},
+	// ALR
+	0x4B: {Name: "*ALR", Dispatch: Alr, AddressingMode: Immediate, Cycles: 2},
+	// ARR
+	0x6B: {Name: "*ARR", Dispatch: Arr, AddressingMode: Immediate, Cycles: 2},
+	// AXS
+	0xCB: {Name: "*AXS", Dispatch: Axs, AddressingMode: Immediate, Cycles: 2},
+	// XAA
+	0x8B: {Name: "*XAA", Dispatch: Xaa, AddressingMode: Immediate, Cycles: 2},
+	// LAS
+	0xBB: {Name: "*LAS", Dispatch: Las, AddressingMode: YIndexedAbsoluteValue, Cycles: 4},
+	// SHA
+	0x9F: {Name: "*SHA", Dispatch: Sha, AddressingMode: YIndexedAbsolute, Cycles: 5},
+	0x93: {Name: "*SHA", Dispatch: Sha, AddressingMode: ZeroPageIndirectYIndexed, Cycles: 6},
+	// SHX
+	0x9E: {Name: "*SHX", Dispatch: Shx, AddressingMode: YIndexedAbsolute, Cycles: 5},
+	// SHY
+	0x9C: {Name: "*SHY", Dispatch: Shy, AddressingMode: XIndexedAbsolute, Cycles: 5},
+	// TAS
+	0x9B: {Name: "*TAS", Dispatch: Tas, AddressingMode: YIndexedAbsolute, Cycles: 5},
+	// JAM
+	0x02: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x12: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x22: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x32: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x42: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x52: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x62: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x72: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0x92: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0xB2: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0xD2: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
+	0xF2: {Name: "*JAM", Dispatch: Jam, AddressingMode: Implied, Cycles: 2},
 	// NOP 🛑
 	0xEA: {Name: "NOP", Dispatch: Nop, AddressingMode: Implied, Cycles: 2},
 	0x1A: {Name: "*NOP", Dispatch: Nop, AddressingMode: Implied, Cycles: 2},
@@ -363,20 +398,20 @@ var instructionMap = [256]Instruction{
 	0x89: {Name: "*NOP", Dispatch: Nop, AddressingMode: Immediate, Cycles: 2},
 	0xC2: {Name: "*NOP", Dispatch: Nop, AddressingMode: Immediate, Cycles: 2},
 	0xE2: {Name: "*NOP", Dispatch: Nop, AddressingMode: Immediate, Cycles: 2},
-	0x0C: {Name: "*NOP", Dispatch: Nop, AddressingMode: Absolute, Cycles: 4},
+	0x0C: {Name: "*NOP", Dispatch: Nop, AddressingMode: AbsoluteValue, Cycles: 4},
 	0x1C: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
 	0x3C: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
 	0x5C: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
 	0x7C: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
 	0xDC: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
 	0xFC: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedAbsoluteValue, Cycles: 4},
-	0x04: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPage, Cycles: 3},
-	0x44: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPage, Cycles: 3},
-	0x64: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPage, Cycles: 3},
-	0x14: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
-	0x34: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
-	0x54: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
-	0x74: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
-	0xD4: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
-	0xF4: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPage, Cycles: 4},
+	0x04: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPageValue, Cycles: 3},
+	0x44: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPageValue, Cycles: 3},
+	0x64: {Name: "*NOP", Dispatch: Nop, AddressingMode: ZeroPageValue, Cycles: 3},
+	0x14: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
+	0x34: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
+	0x54: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
+	0x74: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
+	0xD4: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
+	0xF4: {Name: "*NOP", Dispatch: Nop, AddressingMode: XIndexedZeroPageValue, Cycles: 4},
 }
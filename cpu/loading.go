@@ -56,3 +56,47 @@ func Sax(cpu *CPU, fetchedValue uint16) {
 	value := cpu.A & cpu.X
 	cpu.BusWrite(fetchedValue, value)
 }
+
+func Las(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction LAS...")
+
+	value := uint8(fetchedValue) & cpu.Sp
+	cpu.A = value
+	cpu.X = value
+	cpu.Sp = value
+	cpu.SetStatusFlag(StatusFlagZero, value == 0)
+	cpu.SetStatusFlag(StatusFlagNegative, (value>>7) == 1)
+}
+
+func Sha(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction SHA...")
+	storeAndHigh(cpu, fetchedValue, cpu.Y, cpu.A&cpu.X)
+}
+
+func Shx(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction SHX...")
+	storeAndHigh(cpu, fetchedValue, cpu.Y, cpu.X)
+}
+
+func Shy(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction SHY...")
+	storeAndHigh(cpu, fetchedValue, cpu.X, cpu.Y)
+}
+
+func Tas(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction TAS...")
+	cpu.Sp = cpu.A & cpu.X
+	storeAndHigh(cpu, fetchedValue, cpu.Y, cpu.Sp)
+}
+
+// storeAndHigh stores value ANDed with the high byte of the unindexed
+// address plus one, as the SH* instructions do. When indexing crosses a
+// page, the high byte of the address is replaced by the stored value.
+func storeAndHigh(cpu *CPU, addr uint16, index uint8, value uint8) {
+	baseAddr := addr - uint16(index)
+	value &= uint8(baseAddr>>8) + 1
+	if baseAddr&0xFF00 != addr&0xFF00 {
+		addr = uint16(value)<<8 | addr&0x00FF
+	}
+	cpu.BusWrite(addr, value)
+}
@@ -0,0 +1,126 @@
+package cpu_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLasInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		value     uint16
+		wantValue uint8
+		wantZflag bool
+		wantNflag bool
+	}{
+		{
+			name:      "test stack pointer masks the value",
+			cpu:       cpu.CPU{Sp: 0b11110000},
+			value:     0b10101010,
+			wantValue: 0b10100000,
+			wantNflag: true,
+		},
+		{
+			name:      "test when z flag should be set",
+			cpu:       cpu.CPU{Sp: 0b11110000},
+			value:     0b00001111,
+			wantValue: 0b00000000,
+			wantZflag: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu.Las(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.A)
+			require.Equal(t, test.wantValue, test.cpu.X)
+			require.Equal(t, test.wantValue, test.cpu.Sp)
+			require.Equal(t, test.wantZflag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+			require.Equal(t, test.wantNflag, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+		})
+	}
+}
+
+func TestHighByteStoreInstructions(t *testing.T) {
+	tests := []struct {
+		name        string
+		dispatch    func(*cpu.CPU, uint16)
+		a           uint8
+		x           uint8
+		y           uint8
+		addr        uint16
+		wantAddr    uint16
+		wantValue   uint8
+		wantStackPt uint8
+	}{
+		{
+			name:        "SHA stores A and X and the high byte plus one",
+			dispatch:    cpu.Sha,
+			a:           0b11111111,
+			x:           0b11110111,
+			y:           0x01,
+			addr:        0x0601,
+			wantAddr:    0x0601,
+			wantValue:   0x07,
+			wantStackPt: 0xFD,
+		},
+		{
+			name:        "SHX stores X and the high byte plus one",
+			dispatch:    cpu.Shx,
+			x:           0xFF,
+			y:           0x01,
+			addr:        0x0201,
+			wantAddr:    0x0201,
+			wantValue:   0x03,
+			wantStackPt: 0xFD,
+		},
+		{
+			name:        "SHY stores Y and the high byte plus one",
+			dispatch:    cpu.Shy,
+			x:           0x01,
+			y:           0xFE,
+			addr:        0x0201,
+			wantAddr:    0x0201,
+			wantValue:   0x02,
+			wantStackPt: 0xFD,
+		},
+		{
+			name:        "SHX crossing a page replaces the high byte",
+			dispatch:    cpu.Shx,
+			x:           0x05,
+			y:           0x02,
+			addr:        0x0301,
+			wantAddr:    0x0101,
+			wantValue:   0x01,
+			wantStackPt: 0xFD,
+		},
+		{
+			name:        "TAS sets the stack pointer to A and X",
+			dispatch:    cpu.Tas,
+			a:           0b11001111,
+			x:           0b01111100,
+			y:           0x01,
+			addr:        0x0701,
+			wantAddr:    0x0701,
+			wantValue:   0b00001000,
+			wantStackPt: 0b01001100,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := cpu.NewBus(nil, nil, nil, nil)
+			c := cpu.NewCPU(bus)
+			c.A = test.a
+			c.X = test.x
+			c.Y = test.y
+
+			test.dispatch(c, test.addr)
+			require.Equal(t, test.wantValue, c.BusRead(test.wantAddr))
+			require.Equal(t, test.wantStackPt, c.Sp)
+		})
+	}
+}
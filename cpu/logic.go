@@ -33,3 +33,22 @@ func Ora(cpu *CPU, fetchedValue uint16) {
 	cpu.SetStatusFlag(StatusFlagNegative, (cpu.A>>7) == 1)
 	cpu.SetStatusFlag(StatusFlagZero, cpu.A == 0)
 }
+
+func Anc(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction ANC...")
+
+	And(cpu, fetchedValue)
+	cpu.SetStatusFlag(StatusFlagCarry, (cpu.A>>7) == 1)
+}
+
+// xaaMagic is the value XAA ORs the accumulator with before the ANDs. It
+// depends on the chip and its temperature, 0xEE is the most common one.
+const xaaMagic = 0xEE
+
+func Xaa(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction XAA...")
+
+	cpu.A = (cpu.A | xaaMagic) & cpu.X & uint8(fetchedValue)
+	cpu.SetStatusFlag(StatusFlagZero, cpu.A == 0)
+	cpu.SetStatusFlag(StatusFlagNegative, (cpu.A>>7) == 1)
+}
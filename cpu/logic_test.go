@@ -201,3 +201,94 @@ func TestOraInstruction(t *testing.T) {
 		})
 	}
 }
+
+func TestAncInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		value     uint16
+		wantValue uint8
+		wantZflag bool
+		wantNflag bool
+		wantCflag bool
+	}{
+		{
+			name:      "test when all flags should be reset",
+			cpu:       cpu.CPU{A: 0b10101010},
+			value:     0b00010110,
+			wantValue: 0b00000010,
+		},
+		{
+			name:      "test when z flag should be set",
+			cpu:       cpu.CPU{A: 0b10101010},
+			value:     0b01010101,
+			wantValue: 0b00000000,
+			wantZflag: true,
+		},
+		{
+			name:      "test when n and c flags should be set",
+			cpu:       cpu.CPU{A: 0b10101010},
+			value:     0b10010110,
+			wantValue: 0b10000010,
+			wantNflag: true,
+			wantCflag: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu.Anc(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.A)
+			require.Equal(t, test.wantZflag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+			require.Equal(t, test.wantNflag, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+			require.Equal(t, test.wantCflag, test.cpu.GetStatusFlag(cpu.StatusFlagCarry))
+		})
+	}
+}
+
+func TestXaaInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		value     uint16
+		wantValue uint8
+		wantZflag bool
+		wantNflag bool
+	}{
+		{
+			name:      "test the magic constant clears bits 0 and 4 of A",
+			cpu:       cpu.CPU{A: 0b00000000, X: 0b11111111},
+			value:     0b11111111,
+			wantValue: 0b11101110,
+			wantNflag: true,
+		},
+		{
+			name:      "test when x masks the result",
+			cpu:       cpu.CPU{A: 0b11111111, X: 0b00001111},
+			value:     0b00111100,
+			wantValue: 0b00001100,
+		},
+		{
+			name:      "test when z flag should be set",
+			cpu:       cpu.CPU{A: 0b11111111, X: 0b00010001},
+			value:     0b11111111,
+			wantValue: 0b00010001,
+		},
+		{
+			name:      "test when the operand clears everything",
+			cpu:       cpu.CPU{A: 0b11111111, X: 0b11111111},
+			value:     0b00000000,
+			wantValue: 0b00000000,
+			wantZflag: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu.Xaa(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.A)
+			require.Equal(t, test.wantZflag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+			require.Equal(t, test.wantNflag, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+		})
+	}
+}
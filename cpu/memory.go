@@ -0,0 +1,60 @@
+package cpu
+
+// Memory is the address space the CPU reads and writes, one access per
+// cycle.
+type Memory interface {
+	Read(addr uint16) uint8
+	Write(addr uint16, value uint8)
+}
+
+// Ticker is implemented by memories that need to run along with the CPU.
+// Tick is called once per cycle, before the bus access of the cycle.
+type Ticker interface {
+	Tick()
+}
+
+// Peeker is implemented by memories that can be read without the side
+// effects of a CPU read, for debugging.
+type Peeker interface {
+	Peek(addr uint16) uint8
+}
+
+// Variant is the flavour of 6502 the CPU emulates.
+type Variant uint8
+
+const (
+	// Ricoh2A03 is the NES CPU, which has the decimal mode disabled and
+	// runs the sprite DMA when $4014 is written.
+	Ricoh2A03 Variant = iota
+	// NMOS6502 is a generic NMOS 6502, with decimal mode.
+	NMOS6502
+)
+
+// FlatRAM is a 64KB memory with RAM at every address.
+type FlatRAM struct {
+	data [0x10000]uint8
+}
+
+func NewFlatRAM() *FlatRAM {
+	return &FlatRAM{}
+}
+
+func (r *FlatRAM) Read(addr uint16) uint8 {
+	return r.data[addr]
+}
+
+func (r *FlatRAM) Write(addr uint16, value uint8) {
+	r.data[addr] = value
+}
+
+func (r *FlatRAM) Peek(addr uint16) uint8 {
+	return r.data[addr]
+}
+
+// Load copies data to the memory starting at addr, wrapping around the end
+// of the address space.
+func (r *FlatRAM) Load(addr uint16, data []uint8) {
+	for i, value := range data {
+		r.data[addr+uint16(i)] = value
+	}
+}
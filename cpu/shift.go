@@ -3,7 +3,7 @@ package cpu
 func Asl(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction ASL...")
 
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	result := value << 1
 
 	cpu.BusWrite(fetchedValue, result)
@@ -25,7 +25,7 @@ func AslAccumulator(cpu *CPU, fetchedValue uint16) {
 
 func Lsr(cpu *CPU, fetchedValue uint16) {
 	// fmt.Println("Executing instruction LSR...")
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	result := value >> 1
 	cpu.BusWrite(fetchedValue, result)
 	cpu.SetStatusFlag(StatusFlagCarry, (value&0x01) != 0)
@@ -52,7 +52,7 @@ func Rol(cpu *CPU, fetchedValue uint16) {
 		carryBit = 0
 	}
 
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	result := (value << 1) | carryBit
 
 	cpu.BusWrite(fetchedValue, result)
@@ -88,7 +88,7 @@ func Ror(cpu *CPU, fetchedValue uint16) {
 		carryBit = 0
 	}
 
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	result := (value >> 1) | carryBit
 	cpu.BusWrite(fetchedValue, result)
 
@@ -116,7 +116,7 @@ func RorAccumulator(cpu *CPU, fetchedValue uint16) {
 }
 
 func Slo(cpu *CPU, fetchedValue uint16) {
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	carry := value>>7 == 1
 	value = value << 1
 
@@ -129,7 +129,7 @@ func Slo(cpu *CPU, fetchedValue uint16) {
 }
 
 func Rla(cpu *CPU, fetchedValue uint16) {
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	var currentCarry uint8 = 0
 	if cpu.GetStatusFlag(StatusFlagCarry) {
 		currentCarry = 1
@@ -147,7 +147,7 @@ func Rla(cpu *CPU, fetchedValue uint16) {
 }
 
 func Sre(cpu *CPU, fetchedValue uint16) {
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	carry := value&1 == 1
 	value = value >> 1
 
@@ -160,7 +160,7 @@ func Sre(cpu *CPU, fetchedValue uint16) {
 }
 
 func Rra(cpu *CPU, fetchedValue uint16) {
-	value := cpu.BusRead(fetchedValue)
+	value := cpu.readModify(fetchedValue)
 	var currentCarry uint8 = 0
 	if cpu.GetStatusFlag(StatusFlagCarry) {
 		currentCarry = 1
@@ -170,22 +170,25 @@ func Rra(cpu *CPU, fetchedValue uint16) {
 	value = (value >> 1) | (currentCarry << 7)
 	cpu.SetStatusFlag(StatusFlagCarry, newCarry)
 	cpu.BusWrite(fetchedValue, value)
+	Adc(cpu, uint16(value))
+}
 
-	var carryBit uint16
-	if newCarry {
-		carryBit = 1
-	}
-
-	sum16 := uint16(cpu.A) + uint16(value) + carryBit
-	sum := uint8(sum16)
+func Alr(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction ALR...")
 
-	a := (cpu.A >> 7) == 1
-	s := (sum >> 7) == 1
-	m := (value >> 7) == 1
+	And(cpu, fetchedValue)
+	LsrAccumulator(cpu, 0)
+}
 
-	cpu.SetStatusFlag(StatusFlagNegative, (sum>>7) == 1)
-	cpu.SetStatusFlag(StatusFlagZero, sum == 0)
-	cpu.SetStatusFlag(StatusFlagCarry, sum16 > 0xFF)
-	cpu.SetStatusFlag(StatusFlagOverflow, (!a && !m && s) || (a && m && !s))
-	cpu.A = sum
+func Arr(cpu *CPU, fetchedValue uint16) {
+	// fmt.Println("Executing instruction ARR...")
+
+	And(cpu, fetchedValue)
+	RorAccumulator(cpu, 0)
+	// the carry and overflow come from the adder, which ARR runs on the
+	// result of the rotation
+	bit6 := (cpu.A >> 6) & 1
+	bit5 := (cpu.A >> 5) & 1
+	cpu.SetStatusFlag(StatusFlagCarry, bit6 == 1)
+	cpu.SetStatusFlag(StatusFlagOverflow, bit6^bit5 == 1)
 }
@@ -366,3 +366,110 @@ func TestRorAccumulatorInstruction(t *testing.T) {
 		require.Equal(t, test.wantNFlag, c.GetStatusFlag(cpu.StatusFlagNegative))
 	}
 }
+
+func TestAlrInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		value     uint16
+		wantValue uint8
+		wantCFlag bool
+		wantZFlag bool
+	}{
+		{
+			name:      "test with carry",
+			cpu:       cpu.CPU{A: 0b11111111},
+			value:     0b00000011,
+			wantValue: 0b00000001,
+			wantCFlag: true,
+		},
+		{
+			name:      "test with carry and zero value",
+			cpu:       cpu.CPU{A: 0b00000001},
+			value:     0b11111111,
+			wantValue: 0b00000000,
+			wantCFlag: true,
+			wantZFlag: true,
+		},
+		{
+			name:      "test without carry",
+			cpu:       cpu.CPU{A: 0b11110000},
+			value:     0b10111111,
+			wantValue: 0b01011000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu.Alr(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.A)
+			require.Equal(t, test.wantCFlag, test.cpu.GetStatusFlag(cpu.StatusFlagCarry))
+			require.Equal(t, test.wantZFlag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+			require.False(t, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+		})
+	}
+}
+
+func TestArrInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		cpu       cpu.CPU
+		carry     bool
+		value     uint16
+		wantValue uint8
+		wantCFlag bool
+		wantVFlag bool
+		wantNFlag bool
+		wantZFlag bool
+	}{
+		{
+			name:      "test with bits 6 and 5 set",
+			cpu:       cpu.CPU{A: 0b11111111},
+			value:     0b11000000,
+			wantValue: 0b01100000,
+			wantCFlag: true,
+		},
+		{
+			name:      "test with only bit 6 set",
+			cpu:       cpu.CPU{A: 0b11111111},
+			value:     0b10000000,
+			wantValue: 0b01000000,
+			wantCFlag: true,
+			wantVFlag: true,
+		},
+		{
+			name:      "test with only bit 5 set",
+			cpu:       cpu.CPU{A: 0b11111111},
+			value:     0b01000001,
+			wantValue: 0b00100000,
+			wantVFlag: true,
+		},
+		{
+			name:      "test rotating the carry in",
+			cpu:       cpu.CPU{A: 0b11111111},
+			carry:     true,
+			value:     0b00000001,
+			wantValue: 0b10000000,
+			wantNFlag: true,
+		},
+		{
+			name:      "test zero result",
+			cpu:       cpu.CPU{A: 0b11111111},
+			value:     0b00000001,
+			wantValue: 0b00000000,
+			wantZFlag: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.cpu.SetStatusFlag(cpu.StatusFlagCarry, test.carry)
+			cpu.Arr(&test.cpu, test.value)
+			require.Equal(t, test.wantValue, test.cpu.A)
+			require.Equal(t, test.wantCFlag, test.cpu.GetStatusFlag(cpu.StatusFlagCarry))
+			require.Equal(t, test.wantVFlag, test.cpu.GetStatusFlag(cpu.StatusFlagOverflow))
+			require.Equal(t, test.wantNFlag, test.cpu.GetStatusFlag(cpu.StatusFlagNegative))
+			require.Equal(t, test.wantZFlag, test.cpu.GetStatusFlag(cpu.StatusFlagZero))
+		})
+	}
+}
@@ -12,6 +12,7 @@ func Php(cpu *CPU, _ uint16) {
 
 func Pla(cpu *CPU, _ uint16) {
 	// fmt.Println("Executing instruction PLA...")
+	cpu.BusRead(0x0100 | uint16(cpu.Sp))
 	cpu.A = cpu.Pop()
 	cpu.SetStatusFlag(StatusFlagZero, cpu.A == 0)
 	cpu.SetStatusFlag(StatusFlagNegative, (cpu.A>>7) != 0)
@@ -19,5 +20,6 @@ func Pla(cpu *CPU, _ uint16) {
 
 func Plp(cpu *CPU, _ uint16) {
 	// fmt.Println("Executing instruction PLP...")
+	cpu.BusRead(0x0100 | uint16(cpu.Sp))
 	cpu.P = (cpu.Pop() & 0b11001111) | (cpu.P & 0b00110000)
 }
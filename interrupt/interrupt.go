@@ -20,23 +20,50 @@ func (i Interrupt) String() string {
 	return ""
 }
 
-type interruptSignal struct {
-	wasTriggered bool
-	value        Interrupt
+// Source is a device able to pull the IRQ line.
+type Source uint8
+
+const (
+	SourceMapper Source = 1 << iota
+	SourceFrameCounter
+	SourceDMC
+)
+
+// Lines are the NMI and IRQ inputs of a CPU. The NMI is edge triggered, the
+// CPU only sees it when the line goes active. The IRQ is level triggered
+// and wired-OR, so it stays active while any source holds it.
+type Lines struct {
+	nmi     bool
+	nmiEdge bool
+	irq     Source
 }
 
-func (s *interruptSignal) Read() (Interrupt, bool) {
-	wasTriggered := s.wasTriggered
-	s.wasTriggered = false
-	return s.value, wasTriggered
+// SetNMI sets the level of the NMI line, where true means active.
+func (l *Lines) SetNMI(active bool) {
+	if active && !l.nmi {
+		l.nmiEdge = true
+	}
+	l.nmi = active
+}
+
+// TakeNMIEdge reports whether the NMI line went active since the last
+// call, which is how the CPU edge detector samples it every cycle.
+func (l *Lines) TakeNMIEdge() bool {
+	edge := l.nmiEdge
+	l.nmiEdge = false
+	return edge
 }
 
-func (s *interruptSignal) Send(interrupt Interrupt) {
-	shouldTrigger := !s.wasTriggered || interrupt < s.value
-	if shouldTrigger {
-		s.value = interrupt
+// SetIRQ sets whether source holds the IRQ line.
+func (l *Lines) SetIRQ(source Source, active bool) {
+	if active {
+		l.irq |= source
+	} else {
+		l.irq &^= source
 	}
-	s.wasTriggered = true
 }
 
-var InterruptSignal = interruptSignal{wasTriggered: false}
+// IRQ reports whether any source holds the IRQ line.
+func (l *Lines) IRQ() bool {
+	return l.irq != 0
+}
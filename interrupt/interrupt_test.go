@@ -0,0 +1,47 @@
+package interrupt_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/interrupt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNMIEdge(t *testing.T) {
+	var lines interrupt.Lines
+	require.False(t, lines.TakeNMIEdge())
+
+	lines.SetNMI(true)
+	require.True(t, lines.TakeNMIEdge())
+	require.False(t, lines.TakeNMIEdge(), "the edge is only seen once")
+
+	lines.SetNMI(true)
+	require.False(t, lines.TakeNMIEdge(), "holding the line isn't a new edge")
+
+	lines.SetNMI(false)
+	lines.SetNMI(true)
+	require.True(t, lines.TakeNMIEdge())
+
+	lines.SetNMI(false)
+	lines.SetNMI(true)
+	lines.SetNMI(false)
+	require.True(t, lines.TakeNMIEdge(), "the edge is latched after the line goes inactive")
+}
+
+func TestIRQSources(t *testing.T) {
+	var lines interrupt.Lines
+	require.False(t, lines.IRQ())
+
+	lines.SetIRQ(interrupt.SourceMapper, true)
+	lines.SetIRQ(interrupt.SourceDMC, true)
+	require.True(t, lines.IRQ())
+
+	lines.SetIRQ(interrupt.SourceMapper, false)
+	require.True(t, lines.IRQ(), "the DMC still holds the line")
+
+	lines.SetIRQ(interrupt.SourceFrameCounter, false)
+	require.True(t, lines.IRQ())
+
+	lines.SetIRQ(interrupt.SourceDMC, false)
+	require.False(t, lines.IRQ())
+}
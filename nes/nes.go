@@ -2,58 +2,127 @@ package nes
 
 import (
 	"image"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/LucasWillBlumenau/nes/cartridge"
 	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/interrupt"
 	"github.com/LucasWillBlumenau/nes/joypad"
+	"github.com/LucasWillBlumenau/nes/poweron"
 	"github.com/LucasWillBlumenau/nes/ppu"
+	"github.com/LucasWillBlumenau/nes/trace"
 )
 
 const cpuCycleDuration int64 = 559
 
+// ppuCyclesPerCpuCycle is how many dots the PPU draws during a CPU cycle
+const ppuCyclesPerCpuCycle = 3
+
+// saveInterval is how often the battery backed memory is flushed to disk
+// while playing, so a crash loses at most a few seconds of progress.
+const saveInterval = 10 * time.Second
+
 type NES struct {
-	Frames chan image.RGBA
-	ppu    *ppu.PPU
-	cpu    *cpu.CPU
+	Frames  chan image.RGBA
+	ppu     *ppu.PPU
+	cpu     *cpu.CPU
+	cart    *cartridge.Cartridge
+	reset   atomic.Bool
+	running atomic.Bool
+	// ejectDisk and switchDiskSide hold the disk drive actions requested
+	// from other goroutines until the emulation loop applies them
+	ejectDisk      atomic.Bool
+	switchDiskSide atomic.Bool
+	stop           chan struct{}
+	stopped        chan struct{}
+	closeOnce      sync.Once
+	tracer         *trace.Tracer
 }
 
 func NewNES(
 	frames chan image.RGBA,
-	romPath string,
+	cart *cartridge.Cartridge,
 	scaleFactor int,
 	joypadOne *joypad.Joypad,
 	joypadTwo *joypad.Joypad,
-) (*NES, error) {
-	cart, err := cartridge.LoadCartridgeFromRom(romPath)
-	if err != nil {
-		return nil, err
-	}
-
+	ramState poweron.RamState,
+) *NES {
 	ppuBus := ppu.NewPPUBus(cart)
 	ppu := ppu.NewPPU(ppuBus, frames, scaleFactor)
 	bus := cpu.NewBus(ppu, cart, joypadOne, joypadTwo)
+	bus.FillRam(ramState)
 	cpu := cpu.NewCPU(bus)
+	interrupts := cpu.Interrupts()
+	ppu.SetInterrupts(interrupts)
+	cpu.SetCycleHandler(func() {
+		ppu.RunSteps(ppuCyclesPerCpuCycle)
+		cart.ClockCPU()
+		interrupts.SetIRQ(interrupt.SourceMapper, cart.IRQ())
+	})
+	cpu.SetHaltHandler(func(pc uint16) {
+		log.Printf("cpu halted by the opcode at $%04X, press reset to recover\n", pc)
+	})
 
 	return &NES{
-		Frames: frames,
-		ppu:    ppu,
-		cpu:    cpu,
-	}, nil
+		Frames:  frames,
+		ppu:     ppu,
+		cpu:     cpu,
+		cart:    cart,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
 }
 
 func (n *NES) Run() {
+	n.running.Store(true)
+	defer close(n.stopped)
+
 	n.cpu.Reset()
 	start := time.Now()
+	lastSave := start
 	for {
-		cyclesTaken, err := n.cpu.Run()
-		if err != nil {
+		select {
+		case <-n.stop:
+			return
+		default:
+		}
+
+		if n.reset.Swap(false) {
+			n.cpu.Reset()
+			n.cart.Reset()
+		}
+		if n.ejectDisk.Swap(false) {
+			n.cart.EjectDisk()
+		}
+		if n.switchDiskSide.Swap(false) {
+			if err := n.cart.SwitchDiskSide(); err != nil {
+				log.Printf("error switching disk side: %s\n", err)
+			}
+		}
+
+		if n.tracer != nil {
+			n.tracer.Before()
+		}
+		if _, err := n.cpu.Run(); err != nil {
 			panic(err)
 		}
-		ppuCycles := cyclesTaken * 3
-		n.ppu.RunSteps(ppuCycles)
+		if n.tracer != nil {
+			if err := n.tracer.After(); err != nil {
+				log.Printf("error writing trace: %s\n", err)
+			}
+		}
 
 		currentTime := time.Now()
+		if currentTime.Sub(lastSave) >= saveInterval {
+			if err := n.cart.Save(); err != nil {
+				log.Printf("error saving game: %s\n", err)
+			}
+			lastSave = currentTime
+		}
 		elapsedTime := currentTime.UnixNano() - start.UnixNano()
 		expectedElapsedTime := n.cpu.ElapsedCycles() * cpuCycleDuration
 		if expectedElapsedTime > elapsedTime {
@@ -62,3 +131,47 @@ func (n *NES) Run() {
 		}
 	}
 }
+
+// SetHaltHandler sets the function called, from the emulation loop, when
+// the game runs a JAM opcode. The CPU stays halted until the console is
+// reset, while the PPU keeps drawing frames.
+func (n *NES) SetHaltHandler(handler func(pc uint16)) {
+	n.cpu.SetHaltHandler(handler)
+}
+
+// Trace writes the instructions the CPU runs to w, selected by options. It
+// must be called before Run.
+func (n *NES) Trace(w io.Writer, options trace.Options) {
+	n.tracer = trace.New(w, n.cpu, n.ppu, n.cart, options)
+}
+
+// Reset presses the console reset button. The reset is applied by the
+// emulation loop before the next instruction.
+func (n *NES) Reset() {
+	n.reset.Store(true)
+}
+
+// EjectDisk presses the disk drive eject button, or puts the disk back in
+// when the drive is empty. Cartridges ignore it.
+func (n *NES) EjectDisk() {
+	n.ejectDisk.Store(true)
+}
+
+// SwitchDiskSide puts the next disk side in the drive. Cartridges ignore it.
+func (n *NES) SwitchDiskSide() {
+	n.switchDiskSide.Store(true)
+}
+
+// Close stops the emulation loop and writes the cartridge save data to disk.
+// Calling it again only saves again.
+func (n *NES) Close() error {
+	n.closeOnce.Do(func() { close(n.stop) })
+	for n.running.Load() {
+		select {
+		case <-n.Frames:
+		case <-n.stopped:
+			return n.cart.Save()
+		}
+	}
+	return n.cart.Save()
+}
@@ -0,0 +1,99 @@
+package patch
+
+import "fmt"
+
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+const (
+	bpsCommandMask  = 0b11
+	bpsCommandShift = 2
+)
+
+// applyBPS applies a BPS patch, which builds the target out of runs copied
+// from the source, from the patch itself or from the target built so far.
+func applyBPS(source []byte, patch []byte) ([]byte, error) {
+	sums, err := readChecksums(patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := sums.validateSource(source); err != nil {
+		return nil, err
+	}
+
+	r := &patchReader{data: patch[:len(patch)-checksumsSize], pos: len(bpsMagic)}
+	sourceSize := r.number()
+	targetSize := r.number()
+	r.bytes(r.number()) // metadata
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sourceSize != len(source) {
+		return nil, fmt.Errorf("%w: patch expects a %d bytes rom", ErrInvalidPatch, sourceSize)
+	}
+	if err := checkTargetSize(targetSize); err != nil {
+		return nil, err
+	}
+
+	target := make([]byte, targetSize)
+	out, sourceRel, targetRel := 0, 0, 0
+	for r.remaining() > 0 {
+		action := r.number()
+		length := action>>bpsCommandShift + 1
+		if r.err != nil {
+			return nil, r.err
+		}
+		if out+length > len(target) {
+			return nil, fmt.Errorf("%w: write past the end of the rom", ErrInvalidPatch)
+		}
+
+		switch action & bpsCommandMask {
+		case bpsSourceRead:
+			if out+length > len(source) {
+				return nil, fmt.Errorf("%w: read past the end of the source", ErrInvalidPatch)
+			}
+			copy(target[out:], source[out:out+length])
+		case bpsTargetRead:
+			copy(target[out:], r.bytes(length))
+		case bpsSourceCopy:
+			sourceRel += signedOffset(r.number())
+			if sourceRel < 0 || sourceRel+length > len(source) {
+				return nil, fmt.Errorf("%w: read past the end of the source", ErrInvalidPatch)
+			}
+			copy(target[out:], source[sourceRel:sourceRel+length])
+			sourceRel += length
+		case bpsTargetCopy:
+			targetRel += signedOffset(r.number())
+			if targetRel < 0 || targetRel >= out {
+				return nil, fmt.Errorf("%w: copy from an unwritten part of the rom", ErrInvalidPatch)
+			}
+			// the runs may overlap, repeating the bytes just written
+			for i := range length {
+				target[out+i] = target[targetRel+i]
+			}
+			targetRel += length
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		out += length
+	}
+
+	if err := sums.validateTarget(target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// signedOffset decodes the relative offsets of the copy commands, which
+// keep the sign in the lowest bit.
+func signedOffset(value int) int {
+	if value&1 != 0 {
+		return -(value >> 1)
+	}
+	return value >> 1
+}
@@ -0,0 +1,92 @@
+package patch
+
+import "fmt"
+
+const (
+	ipsOffsetSize = 3
+	ipsSizeSize   = 2
+	// ipsEOF is the "EOF" marker ending the records, read as an offset
+	ipsEOF = 0x454F46
+)
+
+// applyIPS applies the records of an IPS patch. The source is grown as
+// records write past its end, and an optional size after the end marker
+// truncates the result.
+func applyIPS(source []byte, patch []byte) ([]byte, error) {
+	target := append([]byte{}, source...)
+	r := &patchReader{data: patch}
+	for {
+		offset := r.bigEndian(ipsOffsetSize)
+		if r.err != nil {
+			return nil, r.err
+		}
+		if offset == ipsEOF {
+			break
+		}
+		size := r.bigEndian(ipsSizeSize)
+		var data []byte
+		if size == 0 {
+			// run length encoded record
+			size = r.bigEndian(ipsSizeSize)
+			value := r.byte()
+			data = make([]byte, size)
+			for i := range data {
+				data[i] = value
+			}
+		} else {
+			data = r.bytes(size)
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		if end := offset + size; end > len(target) {
+			target = append(target, make([]byte, end-len(target))...)
+		}
+		copy(target[offset:], data)
+	}
+
+	switch r.remaining() {
+	case 0:
+	case ipsOffsetSize:
+		size := r.bigEndian(ipsOffsetSize)
+		if size < len(target) {
+			target = target[:size]
+		}
+	default:
+		return nil, fmt.Errorf("%w: data after the end marker", ErrInvalidPatch)
+	}
+	return target, nil
+}
+
+const ipsMaxRecordSize = 0xFFFF
+
+// CreateIPS returns an IPS patch turning source into target.
+func CreateIPS(source []byte, target []byte) []byte {
+	patch := append([]byte{}, ipsMagic...)
+	for offset := 0; offset < len(target); {
+		if offset < len(source) && source[offset] == target[offset] {
+			offset++
+			continue
+		}
+		// an offset equal to the end marker would end the patch early, so
+		// the record starts one unchanged byte before it instead
+		start := offset
+		if start == ipsEOF {
+			start--
+		}
+		end := offset
+		for end < len(target) && end-start < ipsMaxRecordSize &&
+			(end >= len(source) || source[end] != target[end]) {
+			end++
+		}
+		patch = append(patch, byte(start>>16), byte(start>>8), byte(start))
+		patch = append(patch, byte((end-start)>>8), byte(end-start))
+		patch = append(patch, target[start:end]...)
+		offset = end
+	}
+	patch = append(patch, "EOF"...)
+	if len(target) < len(source) {
+		patch = append(patch, byte(len(target)>>16), byte(len(target)>>8), byte(len(target)))
+	}
+	return patch
+}
@@ -0,0 +1,156 @@
+// Package patch applies the IPS, BPS and UPS patch formats used to
+// distribute translations and rom hacks.
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+var ErrUnknownFormat = errors.New("unknown patch format")
+var ErrInvalidPatch = errors.New("invalid patch")
+var ErrChecksumMismatch = errors.New("patch checksum mismatch")
+
+// Extensions are the file extensions of the supported patch formats.
+var Extensions = []string{".ips", ".bps", ".ups"}
+
+var (
+	ipsMagic = []byte("PATCH")
+	bpsMagic = []byte("BPS1")
+	upsMagic = []byte("UPS1")
+)
+
+// checksumsSize is the size of the source, target and patch CRC32 footer
+// BPS and UPS patches end with.
+const checksumsSize = 12
+
+// maxTargetSize bounds the rom size a BPS or UPS patch can ask for. It is
+// far above any NES rom, and keeps a corrupt size from being allocated.
+const maxTargetSize = 64 << 20
+
+// Apply returns a patched copy of source, picking the patch format from
+// its header. The source is never modified.
+func Apply(source []byte, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, ipsMagic):
+		return applyIPS(source, patch[len(ipsMagic):])
+	case bytes.HasPrefix(patch, bpsMagic):
+		return applyBPS(source, patch)
+	case bytes.HasPrefix(patch, upsMagic):
+		return applyUPS(source, patch)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// patchReader decodes the fields of a patch, remembering the first read
+// past its end so callers only check for errors once per record.
+type patchReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *patchReader) remaining() int {
+	return len(r.data) - r.pos
+}
+
+func (r *patchReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > r.remaining() {
+		r.fail()
+		return nil
+	}
+	data := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return data
+}
+
+func (r *patchReader) byte() uint8 {
+	data := r.bytes(1)
+	if data == nil {
+		return 0
+	}
+	return data[0]
+}
+
+// bigEndian reads an unsigned big endian number of n bytes, as IPS does.
+func (r *patchReader) bigEndian(n int) int {
+	value := 0
+	for _, b := range r.bytes(n) {
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+// number reads the variable length numbers of BPS and UPS, where every
+// byte carries 7 bits and the last one has bit 7 set.
+func (r *patchReader) number() int {
+	value, shift := 0, 1
+	for r.err == nil {
+		b := r.byte()
+		value += int(b&0x7F) * shift
+		if b&0x80 != 0 {
+			break
+		}
+		shift <<= 7
+		value += shift
+		if shift > 1<<42 {
+			r.fail()
+		}
+	}
+	return value
+}
+
+func (r *patchReader) fail() {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: unexpected end of patch at offset %d", ErrInvalidPatch, r.pos)
+	}
+}
+
+type checksums struct {
+	source uint32
+	target uint32
+}
+
+// readChecksums validates the patch CRC32 in the footer and returns the
+// expected source and target ones.
+func readChecksums(patch []byte) (checksums, error) {
+	if len(patch) < checksumsSize {
+		return checksums{}, fmt.Errorf("%w: missing checksums", ErrInvalidPatch)
+	}
+	footer := patch[len(patch)-checksumsSize:]
+	patchCRC := littleEndian32(footer[8:])
+	if crc32.ChecksumIEEE(patch[:len(patch)-4]) != patchCRC {
+		return checksums{}, fmt.Errorf("%w: patch is corrupted", ErrChecksumMismatch)
+	}
+	return checksums{
+		source: littleEndian32(footer[0:]),
+		target: littleEndian32(footer[4:]),
+	}, nil
+}
+
+func (c checksums) validateSource(source []byte) error {
+	if crc32.ChecksumIEEE(source) != c.source {
+		return fmt.Errorf("%w: patch was made for another rom", ErrChecksumMismatch)
+	}
+	return nil
+}
+
+func (c checksums) validateTarget(target []byte) error {
+	if crc32.ChecksumIEEE(target) != c.target {
+		return fmt.Errorf("%w: patched rom is corrupted", ErrChecksumMismatch)
+	}
+	return nil
+}
+
+func littleEndian32(data []byte) uint32 {
+	return uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+}
+
+func checkTargetSize(size int) error {
+	if size > maxTargetSize {
+		return fmt.Errorf("%w: patched rom would have %d bytes", ErrInvalidPatch, size)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package patch_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/patch"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeNumber(value int) []byte {
+	var data []byte
+	for {
+		b := byte(value & 0x7F)
+		value >>= 7
+		if value == 0 {
+			return append(data, b|0x80)
+		}
+		data = append(data, b)
+		value--
+	}
+}
+
+func withChecksums(patch []byte, source []byte, target []byte) []byte {
+	patch = binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(source))
+	patch = binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(target))
+	return binary.LittleEndian.AppendUint32(patch, crc32.ChecksumIEEE(patch))
+}
+
+func join(parts ...[]byte) []byte {
+	var data []byte
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+	return data
+}
+
+func TestIPS(t *testing.T) {
+	source := []byte{0, 1, 2, 3, 4, 5}
+	tests := []struct {
+		name    string
+		patch   []byte
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:  "records",
+			patch: []byte("PATCH\x00\x00\x01\x00\x02\xAA\xBB\x00\x00\x05\x00\x01\xCCEOF"),
+			want:  []byte{0, 0xAA, 0xBB, 3, 4, 0xCC},
+		},
+		{
+			name:  "run length record growing the rom",
+			patch: []byte("PATCH\x00\x00\x04\x00\x00\x00\x04\xEEEOF"),
+			want:  []byte{0, 1, 2, 3, 0xEE, 0xEE, 0xEE, 0xEE},
+		},
+		{
+			name:  "truncation",
+			patch: []byte("PATCHEOF\x00\x00\x03"),
+			want:  []byte{0, 1, 2},
+		},
+		{
+			name:    "missing end marker",
+			patch:   []byte("PATCH\x00\x00\x01\x00\x02\xAA"),
+			wantErr: patch.ErrInvalidPatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := patch.Apply(source, test.patch)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+			require.Equal(t, []byte{0, 1, 2, 3, 4, 5}, source, "the source is left untouched")
+		})
+	}
+}
+
+func TestUPS(t *testing.T) {
+	source := []byte{1, 2, 3, 4, 5, 6}
+	target := []byte{1, 7, 3, 4, 5, 6, 9}
+	body := join(
+		[]byte("UPS1"),
+		encodeNumber(len(source)),
+		encodeNumber(len(target)),
+		encodeNumber(1), []byte{2 ^ 7, 0},
+		encodeNumber(3), []byte{9, 0},
+	)
+	valid := withChecksums(body, source, target)
+
+	got, err := patch.Apply(source, valid)
+	require.NoError(t, err)
+	require.Equal(t, target, got)
+
+	_, err = patch.Apply([]byte{1, 2, 3, 4, 5, 7}, valid)
+	require.ErrorIs(t, err, patch.ErrChecksumMismatch)
+
+	corrupted := append([]byte{}, valid...)
+	corrupted[len(body)-2] ^= 0xFF
+	_, err = patch.Apply(source, corrupted)
+	require.ErrorIs(t, err, patch.ErrChecksumMismatch)
+}
+
+func TestBPS(t *testing.T) {
+	source := []byte("abcdefgh")
+	target := []byte("abcXYZXYZXefgh")
+	action := func(command int, length int) []byte {
+		return encodeNumber((length-1)<<2 | command)
+	}
+	body := join(
+		[]byte("BPS1"),
+		encodeNumber(len(source)),
+		encodeNumber(len(target)),
+		encodeNumber(4), []byte("meta"),
+		action(0, 3),                // source read "abc"
+		action(1, 3), []byte("XYZ"), // target read "XYZ"
+		action(3, 4), encodeNumber(3<<1), // target copy "XYZX" from offset 3
+		action(2, 4), encodeNumber(4<<1), // source copy "efgh" from offset 4
+	)
+
+	got, err := patch.Apply(source, withChecksums(body, source, target))
+	require.NoError(t, err)
+	require.Equal(t, target, got)
+
+	_, err = patch.Apply(source, withChecksums(body, source, []byte("wrong")))
+	require.ErrorIs(t, err, patch.ErrChecksumMismatch)
+}
+
+func TestOversizedTarget(t *testing.T) {
+	source := []byte{1, 2, 3}
+	for _, magic := range []string{"UPS1", "BPS1"} {
+		t.Run(magic, func(t *testing.T) {
+			body := join([]byte(magic), encodeNumber(len(source)), encodeNumber(1<<40), encodeNumber(0))
+			_, err := patch.Apply(source, withChecksums(body, source, nil))
+			require.ErrorIs(t, err, patch.ErrInvalidPatch)
+		})
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := patch.Apply([]byte{1}, []byte("not a patch"))
+	require.ErrorIs(t, err, patch.ErrUnknownFormat)
+}
+
+func TestCreateIPS(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []byte
+		target []byte
+	}{
+		{name: "same data", source: []byte{1, 2, 3}, target: []byte{1, 2, 3}},
+		{name: "changed runs", source: []byte{1, 2, 3, 4, 5, 6}, target: []byte{1, 9, 9, 4, 5, 7}},
+		{name: "grown", source: []byte{1, 2}, target: []byte{1, 2, 3, 4}},
+		{name: "truncated", source: []byte{1, 2, 3, 4}, target: []byte{1, 5}},
+		{name: "long run", source: make([]byte, 0x20000), target: bytes.Repeat([]byte{1}, 0x20000)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := patch.Apply(test.source, patch.CreateIPS(test.source, test.target))
+			require.NoError(t, err)
+			require.Equal(t, test.target, got)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package patch
+
+import "fmt"
+
+// applyUPS applies an UPS patch, which stores the XOR between source and
+// target at the offsets they differ.
+func applyUPS(source []byte, patch []byte) ([]byte, error) {
+	sums, err := readChecksums(patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := sums.validateSource(source); err != nil {
+		return nil, err
+	}
+
+	r := &patchReader{data: patch[:len(patch)-checksumsSize], pos: len(upsMagic)}
+	sourceSize := r.number()
+	targetSize := r.number()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sourceSize != len(source) {
+		return nil, fmt.Errorf("%w: patch expects a %d bytes rom", ErrInvalidPatch, sourceSize)
+	}
+	if err := checkTargetSize(targetSize); err != nil {
+		return nil, err
+	}
+
+	target := make([]byte, targetSize)
+	copy(target, source)
+	pos := 0
+	for r.remaining() > 0 {
+		pos += r.number()
+		for r.err == nil {
+			value := r.byte()
+			if value == 0 {
+				pos++
+				break
+			}
+			if pos < len(target) {
+				target[pos] ^= value
+			}
+			pos++
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+
+	if err := sums.validateTarget(target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
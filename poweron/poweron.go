@@ -0,0 +1,87 @@
+// Package poweron describes what RAM holds when the console is turned on.
+// Real chips come up with unpredictable contents, and a few games, most of
+// them hacks and translations, only run with some of them.
+package poweron
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// RamState is the content RAM is filled with at power on.
+type RamState int
+
+const (
+	// Zeros clears the memory, the state most emulators use.
+	Zeros RamState = iota
+	// Ones sets every bit.
+	Ones
+	// Random fills the memory with random bytes, like real hardware does.
+	Random
+	// Pattern alternates runs of four $00 and four $FF bytes, the pattern
+	// found on many consoles.
+	Pattern
+)
+
+// patternRun is the length of each run of equal bytes in Pattern
+const patternRun = 4
+
+var ErrUnknownRamState = errors.New("unknown ram state")
+
+var ramStateNames = map[RamState]string{
+	Zeros:   "zeros",
+	Ones:    "ones",
+	Random:  "random",
+	Pattern: "pattern",
+}
+
+// Parse returns the RamState with the given name.
+func Parse(name string) (RamState, error) {
+	for state, stateName := range ramStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return Zeros, fmt.Errorf("%w: %q", ErrUnknownRamState, name)
+}
+
+func (s RamState) String() string {
+	if name, ok := ramStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("RamState(%d)", int(s))
+}
+
+// MarshalText and UnmarshalText let a RamState be used with flag.TextVar.
+func (s RamState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *RamState) UnmarshalText(text []byte) error {
+	state, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// Fill sets memory to the power on state.
+func (s RamState) Fill(memory []byte) {
+	for i := range memory {
+		switch s {
+		case Ones:
+			memory[i] = 0xFF
+		case Random:
+			memory[i] = uint8(rand.Uint32())
+		case Pattern:
+			memory[i] = 0
+			if (i/patternRun)%2 == 1 {
+				memory[i] = 0xFF
+			}
+		default:
+			memory[i] = 0
+		}
+	}
+}
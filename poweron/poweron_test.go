@@ -0,0 +1,38 @@
+package poweron_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/poweron"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFill(t *testing.T) {
+	tests := []struct {
+		state poweron.RamState
+		want  []byte
+	}{
+		{state: poweron.Zeros, want: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{state: poweron.Ones, want: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{state: poweron.Pattern, want: []byte{0, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.state.String(), func(t *testing.T) {
+			memory := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+			test.state.Fill(memory)
+			require.Equal(t, test.want, memory)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, state := range []poweron.RamState{poweron.Zeros, poweron.Ones, poweron.Random, poweron.Pattern} {
+		parsed, err := poweron.Parse(state.String())
+		require.NoError(t, err)
+		require.Equal(t, state, parsed)
+	}
+
+	_, err := poweron.Parse("garbage")
+	require.ErrorIs(t, err, poweron.ErrUnknownRamState)
+}
@@ -16,22 +16,10 @@ const (
 
 const (
 	nametableAddrMask uint16 = 0b1111111111
+	nametablePageSize uint16 = 1024
+	ciramPages               = 2
 )
 
-var horizontalMirroringOffset = []uint16{
-	0: 0,
-	1: 0,
-	2: 0x400,
-	3: 0x400,
-}
-
-var verticalMirroringOffset = []uint16{
-	0: 0,
-	1: 0x400,
-	2: 0,
-	3: 0x400,
-}
-
 type PPUBus struct {
 	cart              *cartridge.Cartridge
 	ram               []uint8
@@ -40,7 +28,7 @@ type PPUBus struct {
 }
 
 func NewPPUBus(cart *cartridge.Cartridge) *PPUBus {
-	ram := make([]uint8, 2*1024)
+	ram := make([]uint8, ciramPages*nametablePageSize)
 	return &PPUBus{
 		cart: cart,
 		ram:  ram,
@@ -49,9 +37,13 @@ func NewPPUBus(cart *cartridge.Cartridge) *PPUBus {
 
 func (b *PPUBus) Write(addr uint16, value uint8) {
 	addr = mirrorAddr(addr)
+	b.cart.SnoopPPUAddress(addr)
 	isWriteToRom := addr < 0x2000
+	isWriteToNametable := addr < 0x3F00
 	if isWriteToRom {
 		b.cart.WriteChrRom(addr, value)
+	} else if isWriteToNametable && b.cart.WriteNametable(addr, value) {
+		return
 	} else if writeAddr, device := b.getAddress(addr); writeAddr != nil {
 		if device == memoryDevicePalette {
 			value &= 0b00111111
@@ -63,9 +55,13 @@ func (b *PPUBus) Write(addr uint16, value uint8) {
 
 func (b *PPUBus) Read(addr uint16) uint8 {
 	addr = mirrorAddr(addr)
+	b.cart.SnoopPPUAddress(addr)
 	isReadFromRom := addr < 0x2000
+	isReadFromNametable := addr < 0x3F00
 	if isReadFromRom {
 		return b.cart.ReadChrRom(addr)
+	} else if value, ok := b.cart.ReadNametable(addr); isReadFromNametable && ok {
+		return value
 	} else if readAddr, _ := b.getAddress(addr); readAddr != nil {
 		return *readAddr
 	}
@@ -75,13 +71,10 @@ func (b *PPUBus) Read(addr uint16) uint8 {
 func (b *PPUBus) getAddress(addr uint16) (*uint8, memoryDevice) {
 	isNameTableAddress := addr < 0x03F00
 	if isNameTableAddress {
-		nameTableIndex := addr >> 10 & 0b11
-		offsets := horizontalMirroringOffset
-		if b.cart.Mirroring() == cartridge.VerticalMirroring {
-			offsets = verticalMirroringOffset
-		}
-		offset := offsets[nameTableIndex]
-		addr := offset + (addr & nametableAddrMask)
+		// pages past the CIRAM are served by the cartridge before getting
+		// here, so only the two console pages are left to resolve
+		page := uint16(b.cart.NametablePage(addr) % ciramPages)
+		addr := page*nametablePageSize + (addr & nametableAddrMask)
 
 		return &b.ram[addr], memoryDeviceNametable
 	}
@@ -96,6 +89,12 @@ func (b *PPUBus) getAddress(addr uint16) (*uint8, memoryDevice) {
 	return &b.foregroundPalette[palette][color], memoryDevicePalette
 }
 
+// ClockScanline forwards the end of a rendered scanline to the cartridge,
+// which some boards use to raise IRQs at a given screen position.
+func (b *PPUBus) ClockScanline() {
+	b.cart.ClockScanline()
+}
+
 func mirrorAddr(addr uint16) uint16 {
 	if addr >= 0x4000 {
 		addr &= 0x3FFF
@@ -35,6 +35,11 @@ const (
 
 	originalWidth  = 256
 	originalHeight = 240
+
+	// scanlineCounterClock is the dot where the sprite pattern fetches
+	// first drive the PPU address bus A12 line high, which is what the
+	// cartridge scanline counters react to
+	scanlineCounterClock = 260
 )
 
 type ppuPorts struct {
@@ -127,6 +132,7 @@ type PPU struct {
 	scaleFactor       int
 	cleanVBlank       bool
 	oddFrame          bool
+	interrupts        *interrupt.Lines
 }
 
 func NewPPU(bus *PPUBus, frameChannel chan image.RGBA, scaleFactor int) *PPU {
@@ -139,10 +145,17 @@ func NewPPU(bus *PPUBus, frameChannel chan image.RGBA, scaleFactor int) *PPU {
 	return ppu
 }
 
+// SetInterrupts connects the PPU to the interrupt lines of the CPU, where
+// it signals the vertical blank through the NMI.
+func (p *PPU) SetInterrupts(interrupts *interrupt.Lines) {
+	p.interrupts = interrupts
+}
+
 func (p *PPU) ReadStatusPort() uint8 {
 	currentStatus := p.ports.status
 	p.cleanVBlank = true
 	p.ports.status &= resetStatusVBlank
+	p.updateNMI()
 	p.registers.writeLatch = false
 	return currentStatus | (p.registers.bufferedData & 0b00011111)
 }
@@ -170,11 +183,7 @@ func (p *PPU) WritePPUControlPort(value uint8) {
 	p.ports.control = newPPUControl(value)
 	p.registers.nametable = p.ports.control.nametable
 	p.tempAddr |= uint16(p.ports.control.nametable) << 10
-
-	vBlankStatus := (p.ports.status & setStatusVBlank) > 0
-	if p.ports.control.nmiEnabled && vBlankStatus {
-		interrupt.InterruptSignal.Send(interrupt.NonMaskableInterrupt)
-	}
+	p.updateNMI()
 }
 
 func (p *PPU) WritePPUMaskPort(value uint8) {
@@ -218,6 +227,16 @@ func (p *PPU) WritePPUDataPort(value uint8) {
 	p.currentAddr.Value += p.ports.control.incrementSize
 }
 
+// FrameCount returns how many frames the PPU finished since power on.
+func (p *PPU) FrameCount() uint64 {
+	return p.frameCount
+}
+
+// Position returns the scanline and the dot the PPU draws next.
+func (p *PPU) Position() (scanline uint16, dot uint16) {
+	return p.renderingState.scanline, p.renderingState.clock
+}
+
 func (p *PPU) RunSteps(cycles uint16) {
 	for range cycles {
 		p.runStep()
@@ -230,6 +249,13 @@ func (p *PPU) runStep() {
 
 	preRenderScanline := p.renderingState.scanline == 261
 
+	isRenderedScanline := preRenderScanline || p.renderingState.scanline < 240
+	if isRenderedScanline &&
+		p.renderingState.clock == scanlineCounterClock &&
+		p.ports.mask.RenderingEnabled() {
+		p.bus.ClockScanline()
+	}
+
 	if preRenderScanline {
 		p.handlePreRenderScanline()
 	} else if p.renderingState.scanline < 240 {
@@ -238,7 +264,7 @@ func (p *PPU) runStep() {
 		if !p.cleanVBlank {
 			p.ports.status |= setStatusVBlank
 		}
-		p.vBlank()
+		p.updateNMI()
 		p.rendering = false
 	}
 }
@@ -246,6 +272,7 @@ func (p *PPU) runStep() {
 func (p *PPU) handlePreRenderScanline() {
 	if p.renderingState.clock == 1 {
 		p.ports.status &= resetStatusVBlank
+		p.updateNMI()
 		p.ports.status &= resetSprite0HitFlag
 		p.ports.status &= resetSpriteOverflowFlag
 		p.frameCount++
@@ -518,11 +545,15 @@ func (p *PPU) getCurrentPixelColor(x int) color.RGBA {
 	return p.bus.GetSpriteColor(fgPixel.Palette, fgPixel.Color)
 }
 
-func (p *PPU) vBlank() {
-	vBlankStatus := (p.ports.status & setStatusVBlank) > 0
-	if p.ports.control.nmiEnabled && vBlankStatus {
-		interrupt.InterruptSignal.Send(interrupt.NonMaskableInterrupt)
+// updateNMI drives the NMI line, which is active while the vertical blank
+// flag and the NMI enable bit are both set. Enabling the NMI during the
+// vertical blank makes a new edge, so the CPU gets another NMI.
+func (p *PPU) updateNMI() {
+	if p.interrupts == nil {
+		return
 	}
+	vBlankStatus := (p.ports.status & setStatusVBlank) > 0
+	p.interrupts.SetNMI(p.ports.control.nmiEnabled && vBlankStatus)
 }
 
 func loadBgFetchingStates() [341]backgroundFetchingState {
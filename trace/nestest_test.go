@@ -0,0 +1,95 @@
+package trace_test
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cartridge"
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/joypad"
+	"github.com/LucasWillBlumenau/nes/ppu"
+	"github.com/LucasWillBlumenau/nes/trace"
+	"github.com/stretchr/testify/require"
+)
+
+// nestest (https://www.qmtpro.com/~nes/misc/nestest.txt) isn't distributed
+// with the emulator. To run the test, copy nestest.nes and its golden log,
+// nestest.log, to the testdata folder.
+const (
+	nestestRomFile = "nestest.nes"
+	nestestLogFile = "nestest.log"
+	// nestestAutomationAddr runs all the tests without a PPU, writing the
+	// results to $02 and $03
+	nestestAutomationAddr = 0xC000
+	// nestestContextLines is how many of the lines before the first
+	// divergence are shown
+	nestestContextLines = 5
+)
+
+func TestNestest(t *testing.T) {
+	golden, err := readLines(filepath.Join("testdata", nestestLogFile))
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("testdata/%s is missing", nestestLogFile)
+	}
+	require.NoError(t, err)
+	cart, err := cartridge.LoadCartridgeFromRom(filepath.Join("testdata", nestestRomFile))
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("testdata/%s is missing", nestestRomFile)
+	}
+	require.NoError(t, err)
+
+	p := ppu.NewPPU(ppu.NewPPUBus(cart), make(chan image.RGBA, 1), 1)
+	bus := cpu.NewBus(p, cart, joypad.New(), joypad.New())
+	c := cpu.NewCPU(bus)
+	p.SetInterrupts(c.Interrupts())
+	c.SetCycleHandler(func() {
+		p.RunSteps(3)
+		cart.ClockCPU()
+	})
+	c.Reset()
+	_, err = c.Run()
+	require.NoError(t, err)
+	c.Pc = nestestAutomationAddr
+
+	for i, want := range golden {
+		state := c.State()
+		scanline, dot := p.Position()
+		cycles := c.ElapsedCycles()
+		_, err := c.Run()
+		require.NoError(t, err, "line %d", i+1)
+
+		got := trace.Nintendulator(c.GetLastInstruction(), state, scanline, dot, cycles)
+		if got != want {
+			start := max(0, i-nestestContextLines)
+			t.Fatalf(
+				"line %d diverges from the golden log\n%s\nwant: %s\ngot:  %s",
+				i+1,
+				strings.Join(golden[start:i], "\n"),
+				want,
+				got,
+			)
+		}
+	}
+	require.Zero(t, bus.Read(0x0002), "official opcodes test failed")
+	require.Zero(t, bus.Read(0x0003), "unofficial opcodes test failed")
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r "))
+	}
+	return lines, scanner.Err()
+}
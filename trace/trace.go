@@ -0,0 +1,13 @@
+// Package trace formats the instructions the CPU runs as text, for
+// comparing the emulator against other emulators and test logs.
+package trace
+
+import "fmt"
+
+// Nintendulator formats a line of the logs of the Nintendulator emulator,
+// the format of the nestest golden log. instruction comes from
+// GetLastInstruction, and the CPU state, PPU position and cycle count are
+// the ones from before the instruction ran.
+func Nintendulator(instruction string, state string, scanline uint16, dot uint16, cycles int64) string {
+	return fmt.Sprintf("%-47s %s PPU:%3d,%3d CYC:%d", instruction, state, scanline, dot, cycles)
+}
@@ -0,0 +1,48 @@
+package trace_test
+
+import (
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/trace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNintendulator(t *testing.T) {
+	memory := cpu.NewFlatRAM()
+	memory.Load(0xC000, []uint8{
+		0x4C, 0xF5, 0xC5, // JMP $C5F5
+	})
+	memory.Load(0xC5F5, []uint8{
+		0xA2, 0x00, // LDX #$00
+		0x86, 0x00, // STX $00
+		0xA1, 0x80, // LDA ($80,X)
+		0xB1, 0x80, // LDA ($80),Y
+		0x4A,       // LSR A
+		0x04, 0xA9, // *NOP $A9
+	})
+	memory.Load(0x0080, []uint8{0x00, 0x02})
+	memory.Load(0x0200, []uint8{0x5A, 0x5B})
+	c := cpu.NewCPU(memory)
+	c.Pc = 0xC000
+	c.Y = 0x01
+
+	want := []string{
+		"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:01 P:24 SP:FD PPU:  0,  0 CYC:0",
+		"C5F5  A2 00     LDX #$00                        A:00 X:00 Y:01 P:24 SP:FD PPU:  0,  9 CYC:3",
+		"C5F7  86 00     STX $00 = 00                    A:00 X:00 Y:01 P:26 SP:FD PPU:  0, 15 CYC:5",
+		"C5F9  A1 80     LDA ($80,X) @ 80 = 0200 = 5A    A:00 X:00 Y:01 P:26 SP:FD PPU:  0, 24 CYC:8",
+		"C5FB  B1 80     LDA ($80),Y = 0200 @ 0201 = 5B  A:5A X:00 Y:01 P:24 SP:FD PPU:  0, 42 CYC:14",
+		"C5FD  4A        LSR A                           A:5B X:00 Y:01 P:24 SP:FD PPU:  0, 57 CYC:19",
+		"C5FE  04 A9    *NOP $A9 = 00                    A:2D X:00 Y:01 P:25 SP:FD PPU:  0, 63 CYC:21",
+	}
+	var lines []string
+	for range want {
+		state := c.State()
+		cycles := c.ElapsedCycles()
+		_, err := c.Run()
+		require.NoError(t, err)
+		lines = append(lines, trace.Nintendulator(c.GetLastInstruction(), state, 0, uint16(cycles*3), cycles))
+	}
+	require.Equal(t, want, lines)
+}
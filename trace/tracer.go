@@ -0,0 +1,135 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+)
+
+// bankSize is the size of the banks shown in the traces, the smallest
+// program rom bank the boards switch.
+const bankSize = 8 * 1024
+
+// PPU is the PPU the tracer shows the position of.
+type PPU interface {
+	Position() (scanline uint16, dot uint16)
+	FrameCount() uint64
+}
+
+// ProgramBanks tells where in the program rom a CPU address is mapped, like
+// cartridge.Cartridge does.
+type ProgramBanks interface {
+	ProgramRomOffset(addr uint16) (int, bool)
+}
+
+// Options select the instructions written to the trace.
+type Options struct {
+	// FirstPc and LastPc limit the trace to the instructions between them.
+	// LastPc defaults to $FFFF when zero.
+	FirstPc uint16
+	LastPc  uint16
+	// StartFrame is the first frame traced, and StopFrame the one where
+	// the trace stops, when it isn't zero.
+	StartFrame uint64
+	StopFrame  uint64
+	// SkipInstructions is how many instructions run before the trace
+	// starts, and MaxInstructions how many are traced, when it isn't zero.
+	SkipInstructions int64
+	MaxInstructions  int64
+}
+
+// Tracer writes the instructions the CPU runs in the Nintendulator format,
+// prefixed with the 8KB program rom bank they run from when the banks are
+// known, or -- otherwise. Before and After are called around every
+// cpu.CPU.Run.
+type Tracer struct {
+	w        io.Writer
+	cpu      *cpu.CPU
+	ppu      PPU
+	banks    ProgramBanks
+	options  Options
+	executed int64
+	traced   int64
+	done     bool
+	// the state from before the instruction ran
+	pc       uint16
+	bank     string
+	state    string
+	scanline uint16
+	dot      uint16
+	frame    uint64
+	cycles   int64
+}
+
+// New creates a tracer writing to w. banks may be nil, leaving the banks
+// out of the trace.
+func New(w io.Writer, c *cpu.CPU, ppu PPU, banks ProgramBanks, options Options) *Tracer {
+	if options.LastPc == 0 {
+		options.LastPc = 0xFFFF
+	}
+	return &Tracer{
+		w:       w,
+		cpu:     c,
+		ppu:     ppu,
+		banks:   banks,
+		options: options,
+	}
+}
+
+// Before records the state the next instruction starts from.
+func (t *Tracer) Before() {
+	if t.done {
+		return
+	}
+	t.pc = t.cpu.Pc
+	t.state = t.cpu.State()
+	t.scanline, t.dot = t.ppu.Position()
+	t.frame = t.ppu.FrameCount()
+	t.cycles = t.cpu.ElapsedCycles()
+	if t.banks != nil {
+		t.bank = "--:"
+		if offset, ok := t.banks.ProgramRomOffset(t.pc); ok {
+			t.bank = fmt.Sprintf("%02X:", offset/bankSize)
+		}
+	}
+}
+
+// After writes the instruction the CPU ran since Before, when it passes the
+// options. Interrupts and DMA transfers aren't traced.
+func (t *Tracer) After() error {
+	if t.done {
+		return nil
+	}
+	instruction := t.cpu.GetLastInstruction()
+	if instruction == "" {
+		return nil
+	}
+	t.executed++
+
+	if t.options.StopFrame != 0 && t.frame >= t.options.StopFrame {
+		t.done = true
+		return nil
+	}
+	if t.executed <= t.options.SkipInstructions ||
+		t.frame < t.options.StartFrame ||
+		t.pc < t.options.FirstPc ||
+		t.pc > t.options.LastPc {
+		return nil
+	}
+
+	line := Nintendulator(instruction, t.state, t.scanline, t.dot, t.cycles)
+	if _, err := fmt.Fprintf(t.w, "%s%s\n", t.bank, line); err != nil {
+		t.done = true
+		return err
+	}
+	t.traced++
+	t.done = t.options.MaxInstructions != 0 && t.traced >= t.options.MaxInstructions
+	return nil
+}
+
+// Done reports whether the trace reached its end, after which the tracer
+// ignores the instructions.
+func (t *Tracer) Done() bool {
+	return t.done
+}
@@ -0,0 +1,89 @@
+package trace_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/LucasWillBlumenau/nes/cpu"
+	"github.com/LucasWillBlumenau/nes/trace"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePPU finishes a frame every 4 CPU cycles, two NOPs.
+type fakePPU struct {
+	cpu *cpu.CPU
+}
+
+func (p *fakePPU) Position() (uint16, uint16) {
+	return 0, uint16(p.cpu.ElapsedCycles()%4) * 3
+}
+
+func (p *fakePPU) FrameCount() uint64 {
+	return uint64(p.cpu.ElapsedCycles() / 4)
+}
+
+// fakeBanks maps $8000-$FFFF to the program rom from its second 16KB bank.
+type fakeBanks struct{}
+
+func (fakeBanks) ProgramRomOffset(addr uint16) (int, bool) {
+	if addr < 0x8000 {
+		return 0, false
+	}
+	return int(addr-0x8000) + 0x4000, true
+}
+
+func TestTracer(t *testing.T) {
+	tests := []struct {
+		name    string
+		options trace.Options
+		wantPcs []string
+	}{
+		{name: "everything", wantPcs: []string{"8000", "8001", "8002", "8003", "8004", "8005", "8006", "8007"}},
+		{name: "pc range", options: trace.Options{FirstPc: 0x8002, LastPc: 0x8004}, wantPcs: []string{"8002", "8003", "8004"}},
+		{name: "pc range without end", options: trace.Options{FirstPc: 0x8006}, wantPcs: []string{"8006", "8007"}},
+		{name: "frames", options: trace.Options{StartFrame: 1, StopFrame: 3}, wantPcs: []string{"8002", "8003", "8004", "8005"}},
+		{name: "instructions", options: trace.Options{SkipInstructions: 2, MaxInstructions: 3}, wantPcs: []string{"8002", "8003", "8004"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			memory := cpu.NewFlatRAM()
+			memory.Load(0x8000, bytes.Repeat([]uint8{0xEA}, 8))
+			c := cpu.NewCPU(memory)
+			c.Pc = 0x8000
+
+			var out strings.Builder
+			tracer := trace.New(&out, c, &fakePPU{cpu: c}, fakeBanks{}, test.options)
+			for range 8 {
+				tracer.Before()
+				_, err := c.Run()
+				require.NoError(t, err)
+				require.NoError(t, tracer.After())
+			}
+
+			var pcs []string
+			for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+				require.True(t, strings.HasPrefix(line, "02:"), line)
+				pcs = append(pcs, line[3:7])
+			}
+			require.Equal(t, test.wantPcs, pcs)
+		})
+	}
+}
+
+func TestTracerWithoutBanks(t *testing.T) {
+	memory := cpu.NewFlatRAM()
+	memory.Load(0x0200, []uint8{0xEA})
+	c := cpu.NewCPU(memory)
+	c.Pc = 0x0200
+
+	var out strings.Builder
+	tracer := trace.New(&out, c, &fakePPU{cpu: c}, nil, trace.Options{MaxInstructions: 1})
+	tracer.Before()
+	_, err := c.Run()
+	require.NoError(t, err)
+	require.NoError(t, tracer.After())
+	require.True(t, tracer.Done())
+	require.Equal(t, "0200  EA        NOP                             A:00 X:00 Y:00 P:24 SP:FD PPU:  0,  0 CYC:0\n", out.String())
+}
@@ -41,6 +41,28 @@ var playerOneKeyboardMap = map[sdl.Keycode]joypad.Button{
 	sdl.K_BACKSPACE: joypad.ButtonSelect,
 }
 
+// Console is the set of console actions that can be triggered from the
+// keyboard while the window is focused.
+type Console interface {
+	Reset()
+}
+
+var consoleKeyboardMap = map[sdl.Keycode]func(Console){
+	sdl.K_r: Console.Reset,
+}
+
+// DiskConsole is implemented by consoles with a disk drive attached, like
+// the Famicom Disk System.
+type DiskConsole interface {
+	EjectDisk()
+	SwitchDiskSide()
+}
+
+var diskKeyboardMap = map[sdl.Keycode]func(DiskConsole){
+	sdl.K_e: DiskConsole.EjectDisk,
+	sdl.K_f: DiskConsole.SwitchDiskSide,
+}
+
 type WindowSize struct {
 	Width  int
 	Heigth int
@@ -52,6 +74,7 @@ type Window struct {
 	imagesCh              chan image.RGBA
 	joypadOne             *joypad.Joypad
 	joypadTwo             *joypad.Joypad
+	console               Console
 	playerOneControllerId int
 	playerTwoControllerId int
 }
@@ -74,6 +97,11 @@ func NewWindow(
 	}
 }
 
+// BindConsole makes the console hotkeys act on the given console.
+func (w *Window) BindConsole(console Console) {
+	w.console = console
+}
+
 func (w *Window) Show() {
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		log.Fatalf("error initializing SDL: %s", err)
@@ -128,8 +156,10 @@ func (w *Window) handleEvent(event sdl.Event) bool {
 	switch event.GetType() {
 	case sdl.QUIT:
 		return true
-	case sdl.KEYDOWN,
-		sdl.KEYUP,
+	case sdl.KEYDOWN:
+		w.handleConsoleKeys(event)
+		w.updateJoypadButtonsState(event)
+	case sdl.KEYUP,
 		sdl.JOYBUTTONDOWN,
 		sdl.JOYBUTTONUP,
 		sdl.JOYHATMOTION:
@@ -164,6 +194,21 @@ func (w *Window) handleEvent(event sdl.Event) bool {
 	return false
 }
 
+func (w *Window) handleConsoleKeys(event sdl.Event) {
+	keyboardEvent, ok := event.(*sdl.KeyboardEvent)
+	if !ok || keyboardEvent.Repeat != 0 || w.console == nil {
+		return
+	}
+	if action, ok := consoleKeyboardMap[keyboardEvent.Keysym.Sym]; ok {
+		action(w.console)
+	}
+	if disk, ok := w.console.(DiskConsole); ok {
+		if action, ok := diskKeyboardMap[keyboardEvent.Keysym.Sym]; ok {
+			action(disk)
+		}
+	}
+}
+
 func (w *Window) updateJoypadButtonsState(event sdl.Event) {
 	switch event := event.(type) {
 	case *sdl.JoyButtonEvent: